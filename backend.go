@@ -61,21 +61,37 @@ func Backend(conf *logical.BackendConfig) (*PluginBackend, error) {
 		Help: "",
 		Paths: framework.PathAppend(
 			configPaths(&b),
+			networkPaths(&b),
+			addressBookPaths(&b),
+			tokenPaths(&b),
+			statusPaths(&b),
+			rpcPaths(&b),
 			accountPaths(&b),
+			historyPaths(&b),
+			estimatePaths(&b),
+			signerPaths(&b),
+			broadcastPaths(&b),
 			convertPaths(&b),
 			erc20Paths(&b),
+			ERC721Paths(&b),
+			erc1155Paths(&b),
+			inventoryPaths(&b),
+			safePaths(&b),
+			userOperationPaths(&b),
 		),
 		PathsSpecial: &logical.Paths{
 			Unauthenticated: []string{
-				"convert",
 				"test",
 			},
 			SealWrapStorage: []string{
 				"accounts/",
+				"config",
+				"networks/",
 			},
 		},
 		Secrets:     []*framework.Secret{},
 		BackendType: logical.TypeLogical,
+		Clean:       b.resetClients,
 	}
 	return &b, nil
 }
@@ -83,6 +99,9 @@ func Backend(conf *logical.BackendConfig) (*PluginBackend, error) {
 // PluginBackend implements the Backend for this plugin
 type PluginBackend struct {
 	*framework.Backend
+	health  endpointHealthCache
+	clients clientCache
+	prices  priceCache
 }
 
 // QualifiedPath prepends the token symbol to the path
@@ -99,5 +118,7 @@ func ContractPath(contract, method string) string {
 func SealWrappedPaths(b *PluginBackend) []string {
 	return []string{
 		QualifiedPath("accounts/"),
+		QualifiedPath("config"),
+		QualifiedPath("networks/"),
 	}
 }
@@ -0,0 +1,189 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/contracts/gasoracle"
+)
+
+const (
+	// EthereumMainnet Chain ID
+	EthereumMainnet string = "1"
+	// Sepolia Chain ID
+	Sepolia string = "11155111"
+	// Holesky Chain ID
+	Holesky string = "17000"
+	// PolygonMainnet Chain ID
+	PolygonMainnet string = "137"
+	// PolygonAmoy Chain ID
+	PolygonAmoy string = "80002"
+	// ArbitrumOne Chain ID
+	ArbitrumOne string = "42161"
+	// ArbitrumSepolia Chain ID
+	ArbitrumSepolia string = "421614"
+	// OptimismMainnet Chain ID
+	OptimismMainnet string = "10"
+	// OptimismSepolia Chain ID
+	OptimismSepolia string = "11155420"
+	// BaseMainnet Chain ID
+	BaseMainnet string = "8453"
+	// BaseSepolia Chain ID
+	BaseSepolia string = "84532"
+	// RootstockMainnet Chain ID
+	RootstockMainnet string = "30"
+	// RootstockTestnet Chain ID
+	RootstockTestnet string = "31"
+	// EthereumClassicMainnet Chain ID
+	EthereumClassicMainnet string = "61"
+	// GethPrivateChains Chain ID
+	GethPrivateChains string = "1337"
+	// Local is the default for localhost
+	Local string = "http://localhost:8545"
+
+	// GasPriceOracle is the OP-stack predeploy that prices the L1 data fee
+	GasPriceOracle string = "0x420000000000000000000000000000000000000F"
+)
+
+// Chain describes a chain the plugin knows about
+type Chain struct {
+	Name     string
+	ChainID  string
+	Symbol   string
+	Decimals uint8
+	EIP1559  bool
+	// CoinGeckoID is the CoinGecko coin that prices the native asset
+	CoinGeckoID string
+	// OPStack chains charge an L1 data fee on top of the L2 execution fee
+	OPStack bool
+}
+
+// Chains is the registry of known chains
+var Chains = []Chain{
+	{Name: "mainnet", ChainID: EthereumMainnet, Symbol: "ETH", Decimals: 18, EIP1559: true, CoinGeckoID: "ethereum"},
+	{Name: "sepolia", ChainID: Sepolia, Symbol: "ETH", Decimals: 18, EIP1559: true, CoinGeckoID: "ethereum"},
+	{Name: "holesky", ChainID: Holesky, Symbol: "ETH", Decimals: 18, EIP1559: true, CoinGeckoID: "ethereum"},
+	{Name: "polygon", ChainID: PolygonMainnet, Symbol: "POL", Decimals: 18, EIP1559: true, CoinGeckoID: "polygon-ecosystem-token"},
+	{Name: "polygon-amoy", ChainID: PolygonAmoy, Symbol: "POL", Decimals: 18, EIP1559: true, CoinGeckoID: "polygon-ecosystem-token"},
+	{Name: "arbitrum", ChainID: ArbitrumOne, Symbol: "ETH", Decimals: 18, EIP1559: true, CoinGeckoID: "ethereum"},
+	{Name: "arbitrum-sepolia", ChainID: ArbitrumSepolia, Symbol: "ETH", Decimals: 18, EIP1559: true, CoinGeckoID: "ethereum"},
+	{Name: "optimism", ChainID: OptimismMainnet, Symbol: "ETH", Decimals: 18, EIP1559: true, OPStack: true, CoinGeckoID: "ethereum"},
+	{Name: "optimism-sepolia", ChainID: OptimismSepolia, Symbol: "ETH", Decimals: 18, EIP1559: true, OPStack: true, CoinGeckoID: "ethereum"},
+	{Name: "base", ChainID: BaseMainnet, Symbol: "ETH", Decimals: 18, EIP1559: true, OPStack: true, CoinGeckoID: "ethereum"},
+	{Name: "base-sepolia", ChainID: BaseSepolia, Symbol: "ETH", Decimals: 18, EIP1559: true, OPStack: true, CoinGeckoID: "ethereum"},
+	{Name: "rootstock", ChainID: RootstockMainnet, Symbol: "RBTC", Decimals: 18, CoinGeckoID: "rootstock"},
+	{Name: "rootstock-testnet", ChainID: RootstockTestnet, Symbol: "tRBTC", Decimals: 18, CoinGeckoID: "rootstock"},
+	{Name: "ethereum-classic", ChainID: EthereumClassicMainnet, Symbol: "ETC", Decimals: 18, CoinGeckoID: "ethereum-classic"},
+	{Name: "geth-dev", ChainID: GethPrivateChains, Symbol: "ETH", Decimals: 18, EIP1559: true, CoinGeckoID: "ethereum"},
+}
+
+// ChainByID returns the registry entry for a chain ID, or nil if the chain is unknown
+func ChainByID(chainID string) *Chain {
+	for i := range Chains {
+		if Chains[i].ChainID == chainID {
+			return &Chains[i]
+		}
+	}
+	return nil
+}
+
+// ResolveChainID accepts a chain ID or the name of a chain in the registry
+func ResolveChainID(input string) string {
+	for _, chain := range Chains {
+		if strings.EqualFold(chain.Name, input) {
+			return chain.ChainID
+		}
+	}
+	return input
+}
+
+// chainIDDescription lists the registry for the chain_id field
+func chainIDDescription() string {
+	var description strings.Builder
+	description.WriteString("The chain ID, or the name of one of these chains:\n\n")
+	for _, chain := range Chains {
+		fmt.Fprintf(&description, "%s - %s (%s)\n", chain.ChainID, chain.Name, chain.Symbol)
+	}
+	description.WriteString("\nIf not given, the chain ID reported by the node is used.")
+	return description.String()
+}
+
+// chainResponse describes a chain in responses; unknown chains only report the ID
+func chainResponse(chainID string) map[string]interface{} {
+	chain := ChainByID(chainID)
+	if chain == nil {
+		return map[string]interface{}{
+			"chain_id": chainID,
+		}
+	}
+	return map[string]interface{}{
+		"chain_id": chain.ChainID,
+		"name":     chain.Name,
+		"symbol":   chain.Symbol,
+		"decimals": chain.Decimals,
+		"eip1559":  chain.EIP1559,
+		"op_stack": chain.OPStack,
+
+		"coingecko_id": chain.CoinGeckoID,
+	}
+}
+
+// L1DataFee returns the fee an OP-stack chain charges for posting a transaction to L1,
+// or nil on other chains. The oracle prices the unsigned transaction.
+func L1DataFee(ctx context.Context, client *ethclient.Client, chainID string, tx *types.Transaction) (*big.Int, error) {
+	chain := ChainByID(chainID)
+	if chain == nil || !chain.OPStack {
+		return nil, nil
+	}
+	var unsigned *types.Transaction
+	if tx.To() != nil {
+		unsigned = types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data())
+	} else {
+		unsigned = types.NewContractCreation(tx.Nonce(), tx.Value(), tx.Gas(), tx.GasPrice(), tx.Data())
+	}
+	var encoded bytes.Buffer
+	if err := unsigned.EncodeRLP(&encoded); err != nil {
+		return nil, err
+	}
+	oracle, err := gasoracle.NewGasPriceOracleCaller(common.HexToAddress(GasPriceOracle), client)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := oracle.GetL1Fee(&bind.CallOpts{Context: ctx}, encoded.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the L1 data fee from the GasPriceOracle: %v", err)
+	}
+	return fee, nil
+}
+
+// withL1DataFee adds the L1 data fee and the resulting maximum total fee to a response
+func withL1DataFee(response *logical.Response, tx *types.Transaction, l1DataFee *big.Int) *logical.Response {
+	if l1DataFee != nil {
+		maxFee := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+		response.Data["l1_data_fee"] = l1DataFee.String()
+		response.Data["max_total_fee"] = maxFee.Add(maxFee, l1DataFee).String()
+	}
+	return response
+}
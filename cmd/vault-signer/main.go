@@ -0,0 +1,264 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command vault-signer serves the signer path of a vault-ethereum mount as a plain
+// JSON-RPC endpoint, so that Foundry, Hardhat and ethers can use it as an external
+// signer. The Vault address and token are read from VAULT_ADDR and VAULT_TOKEN.
+//
+// Requests must be application/json, must name the listen address as their Host and
+// must carry the bearer token, so that web pages can't use the signer through the
+// browser. The token is read from VAULT_SIGNER_TOKEN or -token, or else generated
+// and logged at startup.
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// maxRequestSize is the largest JSON-RPC request that is accepted
+const maxRequestSize = 1 << 20
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  []interface{}   `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type signer struct {
+	client  *api.Client
+	path    string
+	network string
+	// hosts are the Host headers that requests may carry
+	hosts []string
+	// token is the bearer token that requests must carry, unless it is empty
+	token string
+}
+
+// isNotification reports whether a request has no id, and so gets no response
+func (request *rpcRequest) isNotification() bool {
+	return len(request.ID) == 0
+}
+
+func (s *signer) call(request *rpcRequest) *rpcResponse {
+	response := &rpcResponse{JSONRPC: "2.0", ID: request.ID}
+	if request.Method == "" {
+		response.Error = &rpcError{Code: -32600, Message: "method is required"}
+		return response
+	}
+	data := map[string]interface{}{
+		"method": request.Method,
+		"params": request.Params,
+	}
+	if request.Params == nil {
+		data["params"] = []interface{}{}
+	}
+	if s.network != "" {
+		data["network"] = s.network
+	}
+	secret, err := s.client.Logical().Write(s.path, data)
+	if err != nil {
+		response.Error = &rpcError{Code: -32000, Message: vaultError(err)}
+		return response
+	}
+	if secret == nil || secret.Data == nil {
+		response.Error = &rpcError{Code: -32000, Message: "no response from vault"}
+		return response
+	}
+	response.Result = secret.Data["result"]
+	return response
+}
+
+// vaultError returns the errors reported by Vault without the request details
+func vaultError(err error) string {
+	if responseError, ok := err.(*api.ResponseError); ok && len(responseError.Errors) > 0 {
+		return strings.Join(responseError.Errors, "; ")
+	}
+	return err.Error()
+}
+
+// authorize returns an HTTP status and message if a request may not use the signer
+func (s *signer) authorize(r *http.Request) (int, string) {
+	if r.Method != http.MethodPost {
+		return http.StatusMethodNotAllowed, "JSON-RPC requests must be POSTed"
+	}
+	// Browsers send an Origin with requests from web pages, and a page can only name
+	// the listen address as its Host by being served from it
+	if r.Header.Get("Origin") != "" {
+		return http.StatusForbidden, "requests from web pages are refused"
+	}
+	if !contains(s.hosts, r.Host) {
+		return http.StatusForbidden, fmt.Sprintf("the Host must be %s", strings.Join(s.hosts, " or "))
+	}
+	// A web page can't send application/json to another origin without a preflight
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		return http.StatusUnsupportedMediaType, "JSON-RPC requests must be application/json"
+	}
+	if s.token != "" {
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "Bearer ") ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, "Bearer ")), []byte(s.token)) != 1 {
+			return http.StatusUnauthorized, "a valid bearer token is required"
+		}
+	}
+	return http.StatusOK, ""
+}
+
+func (s *signer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status, message := s.authorize(r); status != http.StatusOK {
+		http.Error(w, message, status)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	var result interface{}
+	if len(body) > 0 && body[0] == '[' {
+		var requests []*rpcRequest
+		if err := json.Unmarshal(body, &requests); err != nil {
+			result = &rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: -32700, Message: err.Error()}}
+		} else {
+			responses := make([]*rpcResponse, 0, len(requests))
+			for _, request := range requests {
+				response := s.call(request)
+				if !request.isNotification() {
+					responses = append(responses, response)
+				}
+			}
+			// A batch of notifications gets no response at all
+			if len(responses) > 0 {
+				result = responses
+			}
+		}
+	} else {
+		var request rpcRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			result = &rpcResponse{JSONRPC: "2.0", Error: &rpcError{Code: -32700, Message: err.Error()}}
+		} else if response := s.call(&request); !request.isNotification() {
+			result = response
+		}
+	}
+	if result == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Println(err)
+	}
+}
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8550", "The address to serve JSON-RPC on.")
+	mount := flag.String("mount", "vault-ethereum", "The path the plugin is mounted at.")
+	network := flag.String("network", "", "The network to use instead of the mount's default.")
+	token := flag.String("token", os.Getenv("VAULT_SIGNER_TOKEN"), "The bearer token requests must carry. If unset, one is generated.")
+	noToken := flag.Bool("no-token", false, "Serve without a bearer token, so that any local process can sign.")
+	flag.Parse()
+
+	hosts, err := listenHosts(*listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	s := &signer{
+		client:  client,
+		path:    fmt.Sprintf("%s/signer", strings.Trim(*mount, "/")),
+		network: *network,
+		hosts:   hosts,
+		token:   *token,
+	}
+	switch {
+	case *noToken:
+		s.token = ""
+		log.Printf("serving without a bearer token")
+	case s.token == "":
+		s.token, err = generateToken()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("the bearer token is %s", s.token)
+	}
+	log.Printf("serving %s on %s", s.path, *listen)
+	log.Fatal(http.ListenAndServe(*listen, s))
+}
+
+// listenHosts returns the Host headers that name the listen address. A loopback
+// address may also be called localhost.
+func listenHosts(listen string) ([]string, error) {
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		return nil, fmt.Errorf("the listen address must include a host, such as 127.0.0.1%s", listen)
+	}
+	hosts := []string{listen}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		hosts = append(hosts, net.JoinHostPort("localhost", port))
+	}
+	return hosts, nil
+}
+
+// generateToken returns a random bearer token
+func generateToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func contains(list []string, item string) bool {
+	for _, element := range list {
+		if element == item {
+			return true
+		}
+	}
+	return false
+}
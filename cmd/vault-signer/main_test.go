@@ -0,0 +1,115 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// testSigner returns a signer in front of a stand-in Vault that answers every call
+// with 0x1
+func testSigner(t *testing.T) *signer {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"result": "0x1"}}`))
+	}))
+	t.Cleanup(vault.Close)
+	config := api.DefaultConfig()
+	config.Address = vault.URL
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := listenHosts("127.0.0.1:8550")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &signer{client: client, path: "vault-ethereum/signer", hosts: hosts, token: "secret"}
+}
+
+func TestAuthorize(t *testing.T) {
+	s := testSigner(t)
+	tests := []struct {
+		name    string
+		method  string
+		host    string
+		headers map[string]string
+		want    int
+	}{
+		{name: "authorized", host: "127.0.0.1:8550", want: http.StatusOK},
+		{name: "localhost", host: "localhost:8550", want: http.StatusOK},
+		{name: "charset", host: "127.0.0.1:8550", headers: map[string]string{"Content-Type": "application/json; charset=utf-8"}, want: http.StatusOK},
+		{name: "GET", method: http.MethodGet, host: "127.0.0.1:8550", want: http.StatusMethodNotAllowed},
+		{name: "rebound host", host: "attacker.example:8550", want: http.StatusForbidden},
+		{name: "other port", host: "127.0.0.1:80", want: http.StatusForbidden},
+		{name: "origin", host: "127.0.0.1:8550", headers: map[string]string{"Origin": "https://attacker.example"}, want: http.StatusForbidden},
+		{name: "form", host: "127.0.0.1:8550", headers: map[string]string{"Content-Type": "text/plain"}, want: http.StatusUnsupportedMediaType},
+		{name: "no token", host: "127.0.0.1:8550", headers: map[string]string{"Authorization": ""}, want: http.StatusUnauthorized},
+		{name: "wrong token", host: "127.0.0.1:8550", headers: map[string]string{"Authorization": "Bearer guess"}, want: http.StatusUnauthorized},
+		{name: "bare token", host: "127.0.0.1:8550", headers: map[string]string{"Authorization": "secret"}, want: http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			method := test.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			r := httptest.NewRequest(method, "http://"+test.host+"/", strings.NewReader("{}"))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Authorization", "Bearer secret")
+			for key, value := range test.headers {
+				r.Header.Set(key, value)
+			}
+			if got, message := s.authorize(r); got != test.want {
+				t.Fatalf("authorize = %d (%s), want %d", got, message, test.want)
+			}
+		})
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	s := testSigner(t)
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{name: "request", body: `{"jsonrpc": "2.0", "id": 1, "method": "eth_chainId"}`, status: http.StatusOK, want: `{"jsonrpc":"2.0","id":1,"result":"0x1"}`},
+		{name: "notification", body: `{"jsonrpc": "2.0", "method": "eth_chainId"}`, status: http.StatusNoContent},
+		{name: "batch", body: `[{"jsonrpc": "2.0", "method": "eth_chainId"}, {"jsonrpc": "2.0", "id": "a", "method": "eth_chainId"}]`, status: http.StatusOK, want: `[{"jsonrpc":"2.0","id":"a","result":"0x1"}]`},
+		{name: "batch of notifications", body: `[{"jsonrpc": "2.0", "method": "eth_chainId"}]`, status: http.StatusNoContent},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:8550/", strings.NewReader(test.body))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+			if w.Code != test.status {
+				t.Fatalf("status = %d, want %d", w.Code, test.status)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != test.want {
+				t.Fatalf("response = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
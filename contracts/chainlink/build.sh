@@ -0,0 +1 @@
+abigen --abi=./AggregatorV3.abi --pkg=chainlink --type=AggregatorV3 --out=AggregatorV3.go
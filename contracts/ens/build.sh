@@ -0,0 +1,2 @@
+abigen --abi=./ENSRegistry.abi --pkg=ens --type=Registry --out=ENSRegistry.go
+abigen --abi=./ENSResolver.abi --pkg=ens --type=Resolver --out=ENSResolver.go
@@ -0,0 +1 @@
+abigen --abi=./ERC1155.abi --pkg=erc1155 --type=Erc1155 --out=ERC1155.go
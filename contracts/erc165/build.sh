@@ -0,0 +1 @@
+abigen --abi=./ERC165.abi --pkg=erc165 --type=Erc165 --out=ERC165.go
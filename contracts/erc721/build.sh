@@ -7,6 +7,6 @@
 #      echo $GOFILE
 #      abigen --abi=$filename --pkg=erc721 --out=./$GOFILE.go
 # done
-abigen --abi=./ERC721.abi --pkg=erc721 --out=ERC721.go
-abigen --abi=./ERC721Enumerable.abi --pkg=erc721 --out=ERC721Enumerable.go
-abigen --abi=./ERC721Metadata.abi --pkg=erc721 --out=ERC721Metadata.go
\ No newline at end of file
+# The Erc721 binding covers the core, enumerable and metadata interfaces in one ABI
+jq -s 'add' ./ERC721.abi ./ERC721Enumerable.abi ./ERC721Metadata.abi > /tmp/ERC721Full.abi
+abigen --abi=/tmp/ERC721Full.abi --pkg=erc721 --type=Erc721 --out=ERC721.go
@@ -0,0 +1 @@
+abigen --abi=./GasPriceOracle.abi --pkg=gasoracle --type=GasPriceOracle --out=GasPriceOracle.go
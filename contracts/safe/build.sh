@@ -0,0 +1 @@
+abigen --abi=./GnosisSafe.abi --pkg=safe --type=GnosisSafe --out=GnosisSafe.go
@@ -0,0 +1,531 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+const (
+	// MaxBlockLag is how far an endpoint may fall behind the highest block seen on its network
+	MaxBlockLag uint64 = 5
+	// MaxLatency is the slowest response a healthy endpoint may give
+	MaxLatency = 2 * time.Second
+	// HealthCheckInterval is how long endpoint health is trusted before it is checked again
+	HealthCheckInterval = 30 * time.Second
+	// HealthCheckTimeout bounds a single endpoint's health check
+	HealthCheckTimeout = 5 * time.Second
+)
+
+// EndpointHealth is the result of the last health check of an RPC endpoint
+type EndpointHealth struct {
+	URL         string
+	Healthy     bool
+	Degraded    bool
+	ChainID     string
+	BlockNumber uint64
+	BlockLag    uint64
+	Latency     time.Duration
+	Error       string
+	CheckedAt   time.Time
+}
+
+// endpointHealthCache holds the health of every endpoint the backend has checked
+type endpointHealthCache struct {
+	lock      sync.Mutex
+	endpoints map[string]*EndpointHealth
+	// generation changes on every reset, so checks that straddle one aren't cached
+	generation uint64
+}
+
+// clientCache keeps one connection per RPC URL and credentials open across requests.
+// Websocket endpoints in particular are expensive to dial for every request.
+type clientCache struct {
+	lock    sync.Mutex
+	clients map[string]*rpc.Client
+}
+
+// clientKey keeps the clients of networks that share a URL but not credentials apart
+func clientKey(url string, auth *RPCAuthJSON) string {
+	encoded, _ := json.Marshal(auth)
+	sum := sha256.Sum256(encoded)
+	return url + " " + hex.EncodeToString(sum[:])
+}
+
+// dial returns the pooled client for a URL, connecting if there isn't one yet
+func (b *PluginBackend) dial(url string, auth *RPCAuthJSON) (*rpc.Client, error) {
+	key := clientKey(url, auth)
+	b.clients.lock.Lock()
+	client, ok := b.clients.clients[key]
+	b.clients.lock.Unlock()
+	if ok {
+		return client, nil
+	}
+
+	// Connecting can take until the timeout, so it isn't done under the lock. The
+	// context only bounds connecting, a websocket connection outlives it.
+	ctx, cancel := context.WithTimeout(context.Background(), HealthCheckTimeout)
+	defer cancel()
+	client, err := dialRPC(ctx, url, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	b.clients.lock.Lock()
+	defer b.clients.lock.Unlock()
+	if pooled, ok := b.clients.clients[key]; ok {
+		// Another request connected first
+		client.Close()
+		return pooled, nil
+	}
+	if b.clients.clients == nil {
+		b.clients.clients = make(map[string]*rpc.Client)
+	}
+	b.clients.clients[key] = client
+	return client, nil
+}
+
+// evict closes and forgets the pooled client for a URL so the next request reconnects
+func (b *PluginBackend) evict(url string, auth *RPCAuthJSON) {
+	key := clientKey(url, auth)
+	b.clients.lock.Lock()
+	defer b.clients.lock.Unlock()
+	if client, ok := b.clients.clients[key]; ok {
+		client.Close()
+		delete(b.clients.clients, key)
+	}
+}
+
+// resetClients closes every pooled client and forgets the endpoint health and cached
+// prices, so that changed RPC and price settings take effect on the next request
+func (b *PluginBackend) resetClients(ctx context.Context) {
+	b.clients.lock.Lock()
+	for key, client := range b.clients.clients {
+		client.Close()
+		delete(b.clients.clients, key)
+	}
+	b.clients.lock.Unlock()
+
+	b.health.lock.Lock()
+	b.health.endpoints = nil
+	b.health.generation++
+	b.health.lock.Unlock()
+
+	b.resetPrices()
+}
+
+// rpcAuth returns the credentials for the endpoints of the selected network
+func (config *ConfigJSON) rpcAuth() *RPCAuthJSON {
+	if config.network != nil {
+		return &config.network.RPCAuth
+	}
+	return &config.RPCAuth
+}
+
+// rpcURLs returns the ordered RPC endpoints of the selected network
+func (config *ConfigJSON) rpcURLs() []string {
+	if config.network != nil {
+		return config.network.RPCURLs
+	}
+	return []string{config.RPC}
+}
+
+// client connects to the first healthy endpoint of the selected network
+func (b *PluginBackend) client(ctx context.Context, config *ConfigJSON) (*ethclient.Client, error) {
+	client, err := b.rpcClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(client), nil
+}
+
+// rpcClient returns a client for the selected network. Each call goes to the first
+// healthy endpoint, in the configured order; see failoverTransport.
+func (b *PluginBackend) rpcClient(ctx context.Context, config *ConfigJSON) (*rpc.Client, error) {
+	urls, failures := b.usableEndpoints(ctx, config)
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no healthy RPC endpoint for chain %s: %s", config.ChainID, strings.Join(failures, "; "))
+	}
+	return rpc.DialHTTPWithClient(urls[0], &http.Client{
+		Transport: &failoverTransport{backend: b, config: config},
+	})
+}
+
+// usableEndpoints returns the healthy endpoints of the selected network in the
+// configured order. Endpoints that are only lagging or slow come last, as a last
+// resort; endpoints on the wrong chain or that fail are never used.
+func (b *PluginBackend) usableEndpoints(ctx context.Context, config *ConfigJSON) ([]string, []string) {
+	var healthy, degraded, failures []string
+	for _, endpoint := range b.endpointHealth(ctx, config, false) {
+		switch {
+		case !endpoint.Healthy:
+			failures = append(failures, fmt.Sprintf("%s: %s", endpoint.URL, endpoint.Error))
+		case endpoint.Degraded:
+			degraded = append(degraded, endpoint.URL)
+		default:
+			healthy = append(healthy, endpoint.URL)
+		}
+	}
+	return append(healthy, degraded...), failures
+}
+
+// sendMethods are the calls that aren't retried on another endpoint, since the
+// endpoint that failed may have broadcast them anyway
+var sendMethods = map[string]bool{
+	"eth_sendRawTransaction": true,
+	"eth_sendTransaction":    true,
+	"eth_sendUserOperation":  true,
+}
+
+// rpcMessage is a JSON-RPC request or response passed through failoverTransport
+type rpcMessage struct {
+	Version string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Params  []json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *rpcMessageError  `json:"error,omitempty"`
+}
+
+type rpcMessageError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// failoverTransport answers the HTTP requests of a network's client by forwarding
+// their JSON-RPC calls to the pooled client of each endpoint in turn. An endpoint that
+// fails to answer is marked unhealthy until the next health check, and calls that
+// only read are retried on the next endpoint.
+type failoverTransport struct {
+	backend *PluginBackend
+	config  *ConfigJSON
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	batch := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	var requests []*rpcMessage
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		request := &rpcMessage{}
+		err = json.Unmarshal(body, request)
+		requests = []*rpcMessage{request}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := t.backend.forward(req.Context(), t.config, requests)
+	if err != nil {
+		return nil, err
+	}
+	var encoded []byte
+	if batch {
+		encoded, err = json.Marshal(responses)
+	} else {
+		encoded, err = json.Marshal(responses[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(encoded)),
+		ContentLength: int64(len(encoded)),
+		Request:       req,
+	}, nil
+}
+
+// forward sends calls to the first healthy endpoint that answers them
+func (b *PluginBackend) forward(ctx context.Context, config *ConfigJSON, requests []*rpcMessage) ([]*rpcMessage, error) {
+	retry := true
+	for _, request := range requests {
+		if sendMethods[request.Method] {
+			retry = false
+		}
+	}
+	urls, failures := b.usableEndpoints(ctx, config)
+	for _, url := range urls {
+		client, err := b.dial(url, config.rpcAuth())
+		if err != nil {
+			// Nothing was sent, so any call can go to the next endpoint
+			b.markUnhealthy(config.ChainID, url, err)
+			failures = append(failures, fmt.Sprintf("%s: %v", url, err))
+			continue
+		}
+		responses, err := callEndpoint(ctx, client, requests)
+		if err == nil {
+			return responses, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		b.markUnhealthy(config.ChainID, url, err)
+		b.evict(url, config.rpcAuth())
+		if !retry {
+			return nil, fmt.Errorf("%s: %v", url, err)
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", url, err))
+	}
+	return nil, fmt.Errorf("no healthy RPC endpoint for chain %s: %s", config.ChainID, strings.Join(failures, "; "))
+}
+
+// callEndpoint makes the calls on one endpoint. Errors returned by the node are part
+// of the responses; the error is only set when the endpoint couldn't be reached.
+func callEndpoint(ctx context.Context, client *rpc.Client, requests []*rpcMessage) ([]*rpcMessage, error) {
+	elements := make([]rpc.BatchElem, len(requests))
+	results := make([]json.RawMessage, len(requests))
+	for i, request := range requests {
+		elements[i] = rpc.BatchElem{Method: request.Method, Result: &results[i]}
+		for _, param := range request.Params {
+			elements[i].Args = append(elements[i].Args, param)
+		}
+	}
+	if len(requests) == 1 {
+		elements[0].Error = client.CallContext(ctx, elements[0].Result, elements[0].Method, elements[0].Args...)
+		if _, ok := elements[0].Error.(rpc.Error); elements[0].Error != nil && !ok {
+			return nil, elements[0].Error
+		}
+	} else if err := client.BatchCallContext(ctx, elements); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*rpcMessage, len(requests))
+	for i, request := range requests {
+		response := &rpcMessage{Version: "2.0", ID: request.ID}
+		switch err := elements[i].Error.(type) {
+		case nil:
+			response.Result = results[i]
+			if len(response.Result) == 0 {
+				response.Result = json.RawMessage("null")
+			}
+		case rpc.Error:
+			response.Error = &rpcMessageError{Code: err.ErrorCode(), Message: err.Error()}
+		default:
+			response.Error = &rpcMessageError{Code: -32603, Message: err.Error()}
+		}
+		responses[i] = response
+	}
+	return responses, nil
+}
+
+// endpointHealth returns the health of the network's endpoints in the configured
+// order, checking them again if the cached result is stale or force is set
+func (b *PluginBackend) endpointHealth(ctx context.Context, config *ConfigJSON, force bool) []*EndpointHealth {
+	urls := config.rpcURLs()
+	b.health.lock.Lock()
+	generation := b.health.generation
+	stale := force
+	for _, url := range urls {
+		endpoint, ok := b.health.endpoints[endpointKey(config.ChainID, url)]
+		if !ok || time.Since(endpoint.CheckedAt) > HealthCheckInterval {
+			stale = true
+		}
+	}
+	b.health.lock.Unlock()
+
+	// A reset can clear the cache while the endpoints are being checked. The results
+	// of this check are then only used for this request.
+	checked := make(map[string]*EndpointHealth)
+	if stale {
+		for _, endpoint := range b.checkEndpoints(ctx, urls, config.ChainID, config.rpcAuth()) {
+			checked[endpoint.URL] = endpoint
+		}
+		b.health.lock.Lock()
+		if b.health.generation == generation {
+			if b.health.endpoints == nil {
+				b.health.endpoints = make(map[string]*EndpointHealth)
+			}
+			for _, endpoint := range checked {
+				b.health.endpoints[endpointKey(config.ChainID, endpoint.URL)] = endpoint
+			}
+		}
+		b.health.lock.Unlock()
+	}
+
+	b.health.lock.Lock()
+	defer b.health.lock.Unlock()
+	var health []*EndpointHealth
+	for _, url := range urls {
+		cached, ok := checked[url]
+		if !ok {
+			cached, ok = b.health.endpoints[endpointKey(config.ChainID, url)]
+		}
+		if !ok {
+			health = append(health, &EndpointHealth{URL: url, Error: "not checked yet"})
+			continue
+		}
+		endpoint := *cached
+		health = append(health, &endpoint)
+	}
+	return health
+}
+
+// endpointKey keeps the health of a URL apart for each chain it is expected to serve
+func endpointKey(chainID string, url string) string {
+	return chainID + " " + url
+}
+
+// markUnhealthy keeps an endpoint that failed out of use until it is checked again
+func (b *PluginBackend) markUnhealthy(chainID string, url string, err error) {
+	b.health.lock.Lock()
+	defer b.health.lock.Unlock()
+	endpoint, ok := b.health.endpoints[endpointKey(chainID, url)]
+	if !ok {
+		if b.health.endpoints == nil {
+			b.health.endpoints = make(map[string]*EndpointHealth)
+		}
+		endpoint = &EndpointHealth{URL: url, CheckedAt: time.Now()}
+		b.health.endpoints[endpointKey(chainID, url)] = endpoint
+	}
+	endpoint.Healthy = false
+	endpoint.Error = err.Error()
+}
+
+// checkEndpoints checks every endpoint concurrently and then compares their block heights
+func (b *PluginBackend) checkEndpoints(ctx context.Context, urls []string, chainID string, auth *RPCAuthJSON) []*EndpointHealth {
+	checked := make([]*EndpointHealth, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			checked[i] = b.checkEndpoint(ctx, url, chainID, auth)
+		}(i, url)
+	}
+	wg.Wait()
+
+	var highest uint64
+	for _, endpoint := range checked {
+		if endpoint.Healthy && endpoint.BlockNumber > highest {
+			highest = endpoint.BlockNumber
+		}
+	}
+	for _, endpoint := range checked {
+		if !endpoint.Healthy {
+			continue
+		}
+		endpoint.BlockLag = highest - endpoint.BlockNumber
+		if endpoint.BlockLag > MaxBlockLag || endpoint.Latency > MaxLatency {
+			endpoint.Degraded = true
+		}
+	}
+	return checked
+}
+
+func (b *PluginBackend) checkEndpoint(ctx context.Context, url string, chainID string, auth *RPCAuthJSON) *EndpointHealth {
+	endpoint := &EndpointHealth{
+		URL:       url,
+		CheckedAt: time.Now(),
+	}
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	rpcClient, err := b.dial(url, auth)
+	if err != nil {
+		endpoint.Error = err.Error()
+		return endpoint
+	}
+	client := ethclient.NewClient(rpcClient)
+	nodeChainID, err := client.ChainID(ctx)
+	if err != nil {
+		// A dropped websocket stays broken, so reconnect next time
+		b.evict(url, auth)
+		endpoint.Error = err.Error()
+		return endpoint
+	}
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		b.evict(url, auth)
+		endpoint.Error = err.Error()
+		return endpoint
+	}
+	endpoint.Latency = time.Since(start)
+	endpoint.ChainID = nodeChainID.String()
+	endpoint.BlockNumber = header.Number.Uint64()
+	if expected := util.ValidNumber(chainID); expected == nil || expected.Cmp(nodeChainID) != 0 {
+		endpoint.Error = fmt.Sprintf("the node is on chain %s, not %s", endpoint.ChainID, chainID)
+		return endpoint
+	}
+	endpoint.Healthy = true
+	return endpoint
+}
+
+// verifyChainID asks each node for its chain ID with eth_chainId when a configuration is
+// written. Without a chain ID the nodes' is used. A node that can't be reached or that
+// is on another chain is refused unless force is set, since signing for one chain and
+// broadcasting to another would be silently wrong.
+func verifyChainID(ctx context.Context, chainID string, urls []string, auth *RPCAuthJSON, force bool) (string, error) {
+	if chainID != Empty && util.ValidNumber(chainID) == nil {
+		return Empty, fmt.Errorf("invalid chain_id %s", chainID)
+	}
+	for _, url := range urls {
+		nodeChainID, err := queryChainID(ctx, url, auth)
+		if err != nil {
+			if force {
+				continue
+			}
+			return Empty, fmt.Errorf("can't verify the chain ID with %s: %v - set force to save anyway", url, err)
+		}
+		if chainID == Empty {
+			chainID = nodeChainID.String()
+			continue
+		}
+		if util.ValidNumber(chainID).Cmp(nodeChainID) != 0 && !force {
+			return Empty, fmt.Errorf("%s is on chain %s, not %s - set force to save anyway", url, nodeChainID, chainID)
+		}
+	}
+	if chainID == Empty {
+		return Empty, fmt.Errorf("chain_id is required when the node can't be reached")
+	}
+	return chainID, nil
+}
+
+func queryChainID(ctx context.Context, url string, auth *RPCAuthJSON) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+	client, err := dialRPC(ctx, url, auth)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	return ethclient.NewClient(client).ChainID(ctx)
+}
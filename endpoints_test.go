@@ -0,0 +1,78 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFailover(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "provider outage", http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	var calls int
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "0x10"}`))
+	}))
+	defer up.Close()
+
+	tests := []struct {
+		name      string
+		method    string
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "read", method: "eth_blockNumber", wantCalls: 1},
+		{name: "send", method: "eth_sendRawTransaction", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &PluginBackend{}
+			config := &ConfigJSON{ChainID: EthereumMainnet}
+			config.network = &NetworkJSON{RPCURLs: []string{down.URL, up.URL}}
+			b.health.endpoints = map[string]*EndpointHealth{}
+			for _, url := range config.rpcURLs() {
+				b.health.endpoints[endpointKey(config.ChainID, url)] = &EndpointHealth{URL: url, Healthy: true, CheckedAt: time.Now()}
+			}
+			calls = 0
+
+			client, err := b.rpcClient(context.Background(), config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var result string
+			err = client.CallContext(context.Background(), &result, test.method)
+			if test.wantErr && err == nil {
+				t.Fatal("the call succeeded, want an error")
+			}
+			if !test.wantErr && (err != nil || result != "0x10") {
+				t.Fatalf("the call returned %q, %v, want 0x10", result, err)
+			}
+			if calls != test.wantCalls {
+				t.Fatalf("the healthy endpoint was called %d times, want %d", calls, test.wantCalls)
+			}
+			if b.health.endpoints[endpointKey(config.ChainID, down.URL)].Healthy {
+				t.Fatal("the endpoint that failed is still healthy")
+			}
+		})
+	}
+}
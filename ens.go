@@ -0,0 +1,144 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/contracts/ens"
+)
+
+const (
+	// ENSRegistry is the address of the ENS registry on mainnet and the public testnets
+	ENSRegistry string = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+)
+
+// NameHash computes the EIP-137 namehash of an ENS name
+func NameHash(name string) common.Hash {
+	var node common.Hash
+	if name == Empty {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = common.BytesToHash(crypto.Keccak256(node.Bytes(), labelHash))
+	}
+	return node
+}
+
+// isENSName returns true if the input should be resolved through ENS rather than parsed as hex
+func isENSName(input string) bool {
+	return !common.IsHexAddress(input) && strings.Contains(input, ".")
+}
+
+// resolveENS looks up the address an ENS name points at through the registry of the
+// selected network
+func (b *PluginBackend) resolveENS(ctx context.Context, backend bind.ContractCaller, config *ConfigJSON, name string) (*common.Address, error) {
+	registryAddress := config.ENSRegistry
+	if registryAddress == Empty {
+		registryAddress = ENSRegistry
+	}
+	node := NameHash(name)
+	callOpts := &bind.CallOpts{Context: ctx}
+
+	registry, err := ens.NewRegistryCaller(common.HexToAddress(registryAddress), backend)
+	if err != nil {
+		return nil, err
+	}
+	resolverAddress, err := registry.Resolver(callOpts, node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the resolver for %s: %v", name, err)
+	}
+	if resolverAddress == (common.Address{}) {
+		return nil, fmt.Errorf("%s has no resolver", name)
+	}
+
+	resolver, err := ens.NewResolverCaller(resolverAddress, backend)
+	if err != nil {
+		return nil, err
+	}
+	address, err := resolver.Addr(callOpts, node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %v", name, err)
+	}
+	if address == (common.Address{}) {
+		return nil, fmt.Errorf("%s does not resolve to an address", name)
+	}
+	return &address, nil
+}
+
+// resolveAddress parses an address that may be given as hex, as addressbook:<name> or as an
+// ENS name. The name is returned alongside the address, or is empty if hex was provided.
+func (b *PluginBackend) resolveAddress(ctx context.Context, backend bind.ContractCaller, config *ConfigJSON, input string) (*common.Address, string, error) {
+	if strings.HasPrefix(input, AddressBookPrefix) {
+		if config.storage == nil {
+			return nil, Empty, fmt.Errorf("the address book is not available")
+		}
+		entry, err := readAddressBookEntry(ctx, config.storage, strings.TrimPrefix(input, AddressBookPrefix))
+		if err != nil {
+			return nil, Empty, err
+		}
+		if entry == nil {
+			return nil, Empty, fmt.Errorf("%s is not in the address book", strings.TrimPrefix(input, AddressBookPrefix))
+		}
+		address := common.HexToAddress(entry.Address)
+		if err := config.notReserved(&address); err != nil {
+			return nil, Empty, err
+		}
+		return &address, input, nil
+	}
+	if isENSName(input) {
+		address, err := b.resolveENS(ctx, backend, config, input)
+		if err != nil {
+			return nil, Empty, err
+		}
+		if err := config.notReserved(address); err != nil {
+			return nil, Empty, err
+		}
+		return address, input, nil
+	}
+	address, err := config.parseAddress(input)
+	if err != nil {
+		return nil, Empty, err
+	}
+	return address, Empty, nil
+}
+
+// resolveField resolves an address field on a request
+func (b *PluginBackend) resolveField(ctx context.Context, backend bind.ContractCaller, config *ConfigJSON, data *framework.FieldData, field string) (*common.Address, string, error) {
+	address, name, err := b.resolveAddress(ctx, backend, config, data.Get(field).(string))
+	if err != nil {
+		return nil, Empty, fmt.Errorf("invalid %s: %v", field, err)
+	}
+	return address, name, nil
+}
+
+// withENSName adds the ENS name or address book entry used for an address field to a response
+func withENSName(response *logical.Response, field string, name string) *logical.Response {
+	if strings.HasPrefix(name, AddressBookPrefix) {
+		response.Data[field+"_address_book"] = strings.TrimPrefix(name, AddressBookPrefix)
+	} else if name != Empty {
+		response.Data[field+"_ens_name"] = name
+	}
+	return response
+}
@@ -0,0 +1,36 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestNameHash(t *testing.T) {
+	// The first three are the examples in EIP-137
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "", want: "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{name: "eth", want: "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"},
+		{name: "foo.eth", want: "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+		{name: "vitalik.eth", want: "0xee6c4522aab0003e8d14cd40a6af439055fd2577951148c14b6cea9a53475835"},
+		{name: "Vitalik.ETH", want: "0xee6c4522aab0003e8d14cd40a6af439055fd2577951148c14b6cea9a53475835"},
+	}
+	for _, test := range tests {
+		if got := NameHash(test.name).Hex(); got != test.want {
+			t.Errorf("NameHash(%q) = %s, want %s", test.name, got, test.want)
+		}
+	}
+}
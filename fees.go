@@ -0,0 +1,432 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+const (
+	// FeeTierSlow pays the 10th percentile priority fee of recent blocks
+	FeeTierSlow string = "slow"
+	// FeeTierStandard pays the median priority fee of recent blocks
+	FeeTierStandard string = "standard"
+	// FeeTierFast pays the 90th percentile priority fee of recent blocks
+	FeeTierFast string = "fast"
+	// FeeHistoryBlocks is the number of blocks the fee oracle samples
+	FeeHistoryBlocks int = 20
+	// FeeSourceHistory means fees were derived from eth_feeHistory
+	FeeSourceHistory string = "eth_feeHistory"
+	// FeeSourceGasPrice means the node doesn't support EIP-1559 and eth_gasPrice was used
+	FeeSourceGasPrice string = "eth_gasPrice"
+	// DefaultGasMultiplier leaves room for state to change between estimation and inclusion
+	DefaultGasMultiplier float64 = 1.2
+)
+
+// feeTiers are the tiers in order, with the reward percentile each one pays and the
+// headroom (in eighths) it leaves for the base fee to rise before inclusion; the base
+// fee can rise by at most an eighth per block.
+var feeTiers = []struct {
+	Name       string
+	Percentile float64
+	Headroom   int64
+}{
+	{FeeTierSlow, 10, 8},
+	{FeeTierStandard, 50, 9},
+	{FeeTierFast, 90, 10},
+}
+
+// FeePolicyJSON limits the fees a mount or an account will pay
+type FeePolicyJSON struct {
+	FeeTier              string  `json:"fee_tier"`
+	MaxFeePerGas         string  `json:"max_fee_per_gas"`
+	MinPriorityFeePerGas string  `json:"min_priority_fee_per_gas"`
+	GasMultiplier        float64 `json:"gas_multiplier"`
+	MaxGasLimit          string  `json:"max_gas_limit"`
+}
+
+// FeeEstimate is what the fee oracle suggests for each tier
+type FeeEstimate struct {
+	Source       string
+	BaseFee      *big.Int
+	PriorityFees map[string]*big.Int
+	GasPrices    map[string]*big.Int
+}
+
+// withFeePolicyFields adds the fee policy fields to a path's fields
+func withFeePolicyFields(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+	for name, schema := range map[string]*framework.FieldSchema{
+		"fee_tier": {
+			Type:        framework.TypeString,
+			Description: "The speed to pay for when no gas price is given: slow, standard or fast.",
+		},
+		"max_fee_per_gas": {
+			Type:        framework.TypeString,
+			Description: "The most that will be paid per gas, such as 100 gwei - requests above it are refused.",
+		},
+		"min_priority_fee_per_gas": {
+			Type:        framework.TypeString,
+			Description: "The least that is paid per gas above the base fee, such as 1 gwei.",
+		},
+		"gas_multiplier": {
+			Type:        framework.TypeFloat,
+			Description: fmt.Sprintf("The safety margin applied to gas estimates - defaults to %g.", DefaultGasMultiplier),
+		},
+		"max_gas_limit": {
+			Type:        framework.TypeString,
+			Description: "The most gas a transaction may use - larger gas limits are refused.",
+		},
+	} {
+		fields[name] = schema
+	}
+	return fields
+}
+
+// readFeePolicy updates policy with the fields given on the request
+func readFeePolicy(data *framework.FieldData, policy *FeePolicyJSON) error {
+	if feeTier, ok := data.GetOk("fee_tier"); ok {
+		policy.FeeTier = feeTier.(string)
+	}
+	if policy.FeeTier != Empty && !validFeeTier(policy.FeeTier) {
+		return fmt.Errorf("fee_tier must be %s, %s or %s", FeeTierSlow, FeeTierStandard, FeeTierFast)
+	}
+	if gasMultiplier, ok := data.GetOk("gas_multiplier"); ok {
+		policy.GasMultiplier = gasMultiplier.(float64)
+	}
+	if policy.GasMultiplier != 0 && policy.GasMultiplier < 1 {
+		return fmt.Errorf("gas_multiplier can't be less than 1")
+	}
+	// Fees may be given with a unit, such as 30 gwei, but are stored in wei
+	for field, value := range map[string]*string{
+		"max_fee_per_gas":          &policy.MaxFeePerGas,
+		"min_priority_fee_per_gas": &policy.MinPriorityFeePerGas,
+	} {
+		if raw, ok := data.GetOk(field); ok && raw.(string) != Empty {
+			fee, err := ParseAmount(raw.(string))
+			if err != nil {
+				return fmt.Errorf("invalid %s: %v", field, err)
+			}
+			*value = fee.String()
+		} else if ok {
+			*value = Empty
+		}
+	}
+	if maxGasLimit, ok := data.GetOk("max_gas_limit"); ok {
+		policy.MaxGasLimit = maxGasLimit.(string)
+	}
+	if policy.MaxGasLimit != Empty && util.ValidNumber(policy.MaxGasLimit) == nil {
+		return fmt.Errorf("invalid max_gas_limit")
+	}
+	return nil
+}
+
+func validFeeTier(tier string) bool {
+	for _, feeTier := range feeTiers {
+		if feeTier.Name == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// Response returns the policy for a response
+func (policy *FeePolicyJSON) Response() map[string]interface{} {
+	return map[string]interface{}{
+		"fee_tier":                 policy.FeeTier,
+		"max_fee_per_gas":          policy.MaxFeePerGas,
+		"min_priority_fee_per_gas": policy.MinPriorityFeePerGas,
+		"gas_multiplier":           policy.GasMultiplier,
+		"max_gas_limit":            policy.MaxGasLimit,
+	}
+}
+
+// effectiveFeePolicy combines the mount and account policies: the account's tier wins,
+// and the stricter of each limit applies
+func effectiveFeePolicy(config *ConfigJSON, accountJSON *AccountJSON) (string, *big.Int, *big.Int) {
+	tier := FeeTierStandard
+	var maxFee, minPriorityFee *big.Int
+	policies := []FeePolicyJSON{config.FeePolicy}
+	if accountJSON != nil {
+		policies = append(policies, accountJSON.FeePolicy)
+	}
+	for _, policy := range policies {
+		if policy.FeeTier != Empty {
+			tier = policy.FeeTier
+		}
+		if policy.MaxFeePerGas != Empty {
+			limit := util.ValidNumber(policy.MaxFeePerGas)
+			if maxFee == nil || limit.Cmp(maxFee) < 0 {
+				maxFee = limit
+			}
+		}
+		if policy.MinPriorityFeePerGas != Empty {
+			limit := util.ValidNumber(policy.MinPriorityFeePerGas)
+			if minPriorityFee == nil || limit.Cmp(minPriorityFee) > 0 {
+				minPriorityFee = limit
+			}
+		}
+	}
+	return tier, maxFee, minPriorityFee
+}
+
+// feeEstimate suggests a gas price for each tier from the priority fees paid in recent
+// blocks. Legacy transactions pay their whole gas price, so each tier's price is the
+// next base fee with the tier's headroom plus its priority fee.
+func (b *PluginBackend) feeEstimate(ctx context.Context, config *ConfigJSON) (*FeeEstimate, error) {
+	rpcClient, err := b.rpcClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	estimate := &FeeEstimate{
+		PriorityFees: make(map[string]*big.Int),
+		GasPrices:    make(map[string]*big.Int),
+	}
+
+	var percentiles []float64
+	for _, tier := range feeTiers {
+		percentiles = append(percentiles, tier.Percentile)
+	}
+	var history struct {
+		BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+		Reward        [][]*hexutil.Big `json:"reward"`
+	}
+	err = rpcClient.CallContext(ctx, &history, "eth_feeHistory", hexutil.EncodeUint64(uint64(FeeHistoryBlocks)), "latest", percentiles)
+	if err != nil || len(history.BaseFeePerGas) == 0 || len(history.Reward) == 0 {
+		// Without EIP-1559 every tier pays what the node suggests
+		gasPrice, err := ethclient.NewClient(rpcClient).SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		estimate.Source = FeeSourceGasPrice
+		for _, tier := range feeTiers {
+			estimate.PriorityFees[tier.Name] = big.NewInt(0)
+			estimate.GasPrices[tier.Name] = gasPrice
+		}
+		return estimate, nil
+	}
+
+	estimate.Source = FeeSourceHistory
+	// The last base fee is the one for the next block
+	estimate.BaseFee = history.BaseFeePerGas[len(history.BaseFeePerGas)-1].ToInt()
+	for i, tier := range feeTiers {
+		var rewards []*big.Int
+		for _, blockRewards := range history.Reward {
+			if i < len(blockRewards) && blockRewards[i] != nil {
+				rewards = append(rewards, blockRewards[i].ToInt())
+			}
+		}
+		priorityFee := median(rewards)
+		gasPrice := new(big.Int).Mul(estimate.BaseFee, big.NewInt(tier.Headroom))
+		gasPrice.Div(gasPrice, big.NewInt(8))
+		estimate.PriorityFees[tier.Name] = priorityFee
+		estimate.GasPrices[tier.Name] = gasPrice.Add(gasPrice, priorityFee)
+	}
+	return estimate, nil
+}
+
+func median(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return big.NewInt(0)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Cmp(values[j]) < 0
+	})
+	return new(big.Int).Set(values[len(values)/2])
+}
+
+// gasPrice returns the gas price to use for a request: the gas_price given on the
+// request, or the oracle's price for the policy's tier. Either is refused if it
+// breaks the mount or account fee policy.
+func (b *PluginBackend) gasPrice(ctx context.Context, config *ConfigJSON, accountJSON *AccountJSON, data *framework.FieldData) (*big.Int, error) {
+	var gasPrice *big.Int
+	if gasPriceRaw, ok := data.GetOk("gas_price"); ok {
+		var err error
+		gasPrice, err = ParseAmount(gasPriceRaw.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gas price: %v", err)
+		}
+	}
+	return b.policyGasPrice(ctx, config, accountJSON, gasPrice)
+}
+
+// policyGasPrice checks a gas price against the fee policy, or returns the oracle's
+// price for the policy's tier if the gas price is nil or zero
+func (b *PluginBackend) policyGasPrice(ctx context.Context, config *ConfigJSON, accountJSON *AccountJSON, gasPrice *big.Int) (*big.Int, error) {
+	tier, maxFee, minPriorityFee := effectiveFeePolicy(config, accountJSON)
+	if gasPrice != nil && gasPrice.Sign() == 0 {
+		gasPrice = nil
+	}
+	explicit := gasPrice != nil
+
+	var estimate *FeeEstimate
+	if !explicit || minPriorityFee != nil {
+		var err error
+		estimate, err = b.feeEstimate(ctx, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !explicit {
+		gasPrice = estimate.GasPrices[tier]
+	}
+
+	if minPriorityFee != nil && estimate.BaseFee != nil {
+		floor := new(big.Int).Add(estimate.BaseFee, minPriorityFee)
+		if gasPrice.Cmp(floor) < 0 {
+			if explicit {
+				return nil, fmt.Errorf("gas price %s leaves less than the minimum priority fee of %s over the base fee of %s", gasPrice, minPriorityFee, estimate.BaseFee)
+			}
+			gasPrice = floor
+		}
+	}
+	if maxFee != nil && gasPrice.Cmp(maxFee) > 0 {
+		return nil, fmt.Errorf("gas price %s exceeds the maximum fee per gas of %s", gasPrice, maxFee)
+	}
+	return gasPrice, nil
+}
+
+// contractCall is the contract method that a transaction calls
+type contractCall struct {
+	contract common.Address
+	abi      string
+	method   string
+	args     []interface{}
+}
+
+// withFeePolicy prices a contract transaction according to the fee policy and sets its
+// gas limit according to the gas policy
+func (b *PluginBackend) withFeePolicy(ctx context.Context, client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, data *framework.FieldData, transactOpts *bind.TransactOpts, call *contractCall) (*bind.TransactOpts, error) {
+	gasPrice, err := b.gasPrice(ctx, config, accountJSON, data)
+	if err != nil {
+		return nil, err
+	}
+	transactOpts.GasPrice = gasPrice
+	return b.withGasLimit(ctx, client, config, accountJSON, data, transactOpts, call)
+}
+
+// withGasLimit sets the gas limit of a contract transaction to the gas_limit given on
+// the request or the node's estimate for the call, held to the gas policy. Left at
+// zero, bind would estimate it without the policy.
+func (b *PluginBackend) withGasLimit(ctx context.Context, client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, data *framework.FieldData, transactOpts *bind.TransactOpts, call *contractCall) (*bind.TransactOpts, error) {
+	parsed, err := abi.JSON(strings.NewReader(call.abi))
+	if err != nil {
+		return nil, err
+	}
+	input, err := parsed.Pack(call.method, call.args...)
+	if err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{
+		From:     transactOpts.From,
+		To:       &call.contract,
+		GasPrice: transactOpts.GasPrice,
+		Value:    transactOpts.Value,
+		Data:     input,
+	}
+	gasLimit, err := b.gasLimit(ctx, client, config, accountJSON, data, msg)
+	if err != nil {
+		return nil, err
+	}
+	transactOpts.GasLimit = gasLimit
+	return transactOpts, nil
+}
+
+// gasLimitField is the gas_limit field of a path that sends a contract transaction
+func gasLimitField() *framework.FieldSchema {
+	return &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "The gas limit for the transaction - if omitted, it is estimated.",
+	}
+}
+
+// effectiveGasPolicy combines the mount and account policies: the account's multiplier
+// wins, and the lower gas limit applies
+func effectiveGasPolicy(config *ConfigJSON, accountJSON *AccountJSON) (float64, *big.Int) {
+	multiplier := DefaultGasMultiplier
+	var maxGasLimit *big.Int
+	policies := []FeePolicyJSON{config.FeePolicy}
+	if accountJSON != nil {
+		policies = append(policies, accountJSON.FeePolicy)
+	}
+	for _, policy := range policies {
+		if policy.GasMultiplier != 0 {
+			multiplier = policy.GasMultiplier
+		}
+		if policy.MaxGasLimit != Empty {
+			limit := util.ValidNumber(policy.MaxGasLimit)
+			if maxGasLimit == nil || limit.Cmp(maxGasLimit) < 0 {
+				maxGasLimit = limit
+			}
+		}
+	}
+	return multiplier, maxGasLimit
+}
+
+// gasLimit returns the gas_limit given on the request or, if it is omitted, the node's
+// estimate for the transaction
+func (b *PluginBackend) gasLimit(ctx context.Context, client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, data *framework.FieldData, msg ethereum.CallMsg) (uint64, error) {
+	if gasLimitRaw, ok := data.GetOk("gas_limit"); ok {
+		gasLimit := util.ValidNumber(gasLimitRaw.(string))
+		if gasLimit == nil || !gasLimit.IsUint64() {
+			return 0, fmt.Errorf("invalid gas limit")
+		}
+		msg.Gas = gasLimit.Uint64()
+	}
+	return b.policyGasLimit(ctx, client, config, accountJSON, msg)
+}
+
+// policyGasLimit checks the gas of a call against the gas policy, or estimates it if
+// the call has no gas
+func (b *PluginBackend) policyGasLimit(ctx context.Context, client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, msg ethereum.CallMsg) (uint64, error) {
+	if msg.Gas > 0 {
+		return withGasPolicy(config, accountJSON, msg.Gas, false)
+	}
+	estimate, err := client.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate gas: %v", err)
+	}
+	return withGasPolicy(config, accountJSON, estimate, true)
+}
+
+// withGasPolicy applies the safety multiplier to an estimated gas limit and refuses gas
+// limits above the policy's max_gas_limit. An estimate that only exceeds it because of
+// the multiplier is capped instead.
+func withGasPolicy(config *ConfigJSON, accountJSON *AccountJSON, gasLimit uint64, estimated bool) (uint64, error) {
+	multiplier, maxGasLimit := effectiveGasPolicy(config, accountJSON)
+	limit := gasLimit
+	if estimated {
+		limit = uint64(float64(gasLimit) * multiplier)
+	}
+	if maxGasLimit == nil || maxGasLimit.Cmp(new(big.Int).SetUint64(limit)) >= 0 {
+		return limit, nil
+	}
+	if estimated && maxGasLimit.Cmp(new(big.Int).SetUint64(gasLimit)) >= 0 {
+		return maxGasLimit.Uint64(), nil
+	}
+	return 0, fmt.Errorf("gas limit %d exceeds the maximum gas limit of %s", gasLimit, maxGasLimit)
+}
@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -34,6 +35,7 @@ import (
 	"github.com/tyler-smith/go-bip39"
 
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/cidrutil"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/immutability-io/vault-ethereum/util"
 )
@@ -51,19 +53,28 @@ const (
 
 // AccountJSON is what we store for an Ethereum account
 type AccountJSON struct {
-	Index      int      `json:"index"`
-	Mnemonic   string   `json:"mnemonic"`
-	Inclusions []string `json:"inclusions"`
-	Exclusions []string `json:"exclusions"`
+	Index      int           `json:"index"`
+	Mnemonic   string        `json:"mnemonic"`
+	Inclusions []string      `json:"inclusions"`
+	Exclusions []string      `json:"exclusions"`
+	FeePolicy  FeePolicyJSON `json:"fee_policy"`
+	// BoundCIDRList limits the source addresses that can use the account
+	BoundCIDRList []string `json:"bound_cidr_list"`
+	// ChainIDs limits the chains the account can be used on
+	ChainIDs []string `json:"chain_ids"`
+	// AllowMessageSigning lets the signer sign messages and typed data it can't check
+	AllowMessageSigning bool `json:"allow_message_signing"`
+
+	addressBook addressBook
 }
 
 // ValidAddress returns an error if the address is not included or if it is excluded
 func (account *AccountJSON) ValidAddress(toAddress *common.Address) error {
-	if util.Contains(account.Exclusions, toAddress.Hex()) {
-		return fmt.Errorf("%s is excludeded by this account", toAddress.Hex())
+	if err := account.addressBook.excludes(account.Exclusions, toAddress, "account"); err != nil {
+		return err
 	}
 
-	if len(account.Inclusions) > 0 && !util.Contains(account.Inclusions, toAddress.Hex()) {
+	if len(account.Inclusions) > 0 && !account.addressBook.contains(account.Inclusions, toAddress) {
 		return fmt.Errorf("%s is not in the set of inclusions of this account", toAddress.Hex())
 	}
 	return nil
@@ -71,11 +82,12 @@ func (account *AccountJSON) ValidAddress(toAddress *common.Address) error {
 
 // TransactionParams are typical parameters for a transaction
 type TransactionParams struct {
-	Nonce    uint64          `json:"nonce"`
-	Address  *common.Address `json:"address"`
-	Amount   *big.Int        `json:"amount"`
-	GasPrice *big.Int        `json:"gas_price"`
-	GasLimit uint64          `json:"gas_limit"`
+	Nonce       uint64          `json:"nonce"`
+	Address     *common.Address `json:"address"`
+	AddressName string          `json:"address_name"`
+	Amount      *big.Int        `json:"amount"`
+	GasPrice    *big.Int        `json:"gas_price"`
+	GasLimit    uint64          `json:"gas_limit"`
 }
 
 func accountPaths(b *PluginBackend) []*framework.Path {
@@ -99,7 +111,7 @@ Creates (or updates) an Ethereum account: an account controlled by a private key
 The generator produces a high-entropy passphrase with the provided length and requirements.
 
 `,
-			Fields: map[string]*framework.FieldSchema{
+			Fields: withFeePolicyFields(map[string]*framework.FieldSchema{
 				"name": {Type: framework.TypeString},
 				"mnemonic": {
 					Type:        framework.TypeString,
@@ -119,7 +131,19 @@ The generator produces a high-entropy passphrase with the provided length and re
 					Type:        framework.TypeCommaStringSlice,
 					Description: "The list of accounts that this account can't send transactions to.",
 				},
-			},
+				"bound_cidr_list": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The CIDR blocks that requests using this account must come from.",
+				},
+				"chain_ids": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The chain IDs or chain names this account can be used on - if unset, any.",
+				},
+				"allow_message_signing": {
+					Type:        framework.TypeBool,
+					Description: "Allow the signer to sign raw messages and typed data that can't be checked against the inclusions and exclusions.",
+				},
+			}),
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
 				logical.ReadOperation:   b.pathAccountsRead,
@@ -137,23 +161,23 @@ Send ETH from an account.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"to": {
 					Type:        framework.TypeString,
-					Description: "The address of the wallet to send ETH to.",
+					Description: "The address or ENS name of the wallet to send ETH to.",
 				},
 				"amount": {
 					Type:        framework.TypeString,
-					Description: "Amount of ETH (in wei).",
+					Description: "Amount of ETH - wei, 0x hex wei or a number and a unit, such as 1.5 ether.",
 				},
 				"gas_limit": {
 					Type:        framework.TypeString,
-					Description: "The gas limit for the transaction - defaults to 21000.",
-					Default:     "21000",
+					Description: "The gas limit for the transaction - if omitted, it is estimated.",
 				},
 				"gas_price": {
 					Type:        framework.TypeString,
-					Description: "The gas price for the transaction in wei.",
+					Description: "The gas price for the transaction - wei or a number and a unit, such as 30 gwei.",
 					Default:     "0",
 				},
 			},
@@ -173,6 +197,7 @@ Return the balance in wei for an address.
 `,
 			Fields: map[string]*framework.FieldSchema{
 				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"address": {Type: framework.TypeString},
 			},
 			ExistenceCheck: pathExistenceCheck,
@@ -190,10 +215,11 @@ Sign a transaction.
 `,
 			Fields: map[string]*framework.FieldSchema{
 				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"address": {Type: framework.TypeString},
 				"to": {
 					Type:        framework.TypeString,
-					Description: "The address of the wallet to send ETH to.",
+					Description: "The address or ENS name of the wallet to send ETH to.",
 				},
 				"data": {
 					Type:        framework.TypeString,
@@ -206,7 +232,7 @@ Sign a transaction.
 				},
 				"amount": {
 					Type:        framework.TypeString,
-					Description: "Amount of ETH (in wei).",
+					Description: "Amount of ETH - wei, 0x hex wei or a number and a unit, such as 1.5 ether.",
 				},
 				"nonce": {
 					Type:        framework.TypeString,
@@ -214,12 +240,11 @@ Sign a transaction.
 				},
 				"gas_limit": {
 					Type:        framework.TypeString,
-					Description: "The gas limit for the transaction - defaults to 21000.",
-					Default:     "21000",
+					Description: "The gas limit for the transaction - if omitted, it is estimated.",
 				},
 				"gas_price": {
 					Type:        framework.TypeString,
-					Description: "The gas price for the transaction in wei.",
+					Description: "The gas price for the transaction - wei or a number and a unit, such as 30 gwei.",
 					Default:     "0",
 				},
 			},
@@ -239,6 +264,7 @@ Deploy a smart contract to the network.
 `,
 			Fields: map[string]*framework.FieldSchema{
 				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"address": {Type: framework.TypeString},
 				"version": {
 					Type:        framework.TypeString,
@@ -281,6 +307,7 @@ https://eth.wiki/json-rpc/API#eth_sign
 					Type:        framework.TypeString,
 					Description: "Message to sign.",
 				},
+				"network": networkField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -315,9 +342,58 @@ func readAccount(ctx context.Context, req *logical.Request, name string) (*Accou
 	if entry == nil {
 		return nil, fmt.Errorf("failed to deserialize account at %s", path)
 	}
+	accountJSON.addressBook, err = readAddressBook(ctx, req.Storage, accountJSON.Inclusions, accountJSON.Exclusions)
+	if err != nil {
+		return nil, err
+	}
 	return &accountJSON, nil
 }
 
+// accountFor reads an account that a request uses. Accounts can be restricted to source
+// CIDR blocks and to chain IDs, in addition to the restrictions of the mount.
+func (b *PluginBackend) accountFor(ctx context.Context, req *logical.Request, config *ConfigJSON, name string) (*AccountJSON, error) {
+	accountJSON, err := readAccount(ctx, req, name)
+	if err != nil {
+		return nil, err
+	}
+	if accountJSON == nil {
+		return nil, fmt.Errorf("account %s does not exist", name)
+	}
+	if validConnection, err := validSourceAddress(req, accountJSON.BoundCIDRList, "account "+name); !validConnection {
+		return nil, err
+	}
+	if len(accountJSON.ChainIDs) > 0 && !util.Contains(accountJSON.ChainIDs, config.ChainID) {
+		return nil, fmt.Errorf("account %s can't be used on chain %s", name, config.ChainID)
+	}
+	return accountJSON, nil
+}
+
+// readAccountRestrictions updates the account's CIDR, chain and message signing restrictions with the fields given on the request
+func readAccountRestrictions(data *framework.FieldData, accountJSON *AccountJSON) error {
+	if boundCIDRList, ok := data.GetOk("bound_cidr_list"); ok {
+		accountJSON.BoundCIDRList = boundCIDRList.([]string)
+	}
+	if len(accountJSON.BoundCIDRList) > 0 {
+		if valid, err := cidrutil.ValidateCIDRListSlice(accountJSON.BoundCIDRList); !valid {
+			return fmt.Errorf("invalid bound_cidr_list: %v", err)
+		}
+	}
+	if chainIDs, ok := data.GetOk("chain_ids"); ok {
+		accountJSON.ChainIDs = nil
+		for _, chainID := range chainIDs.([]string) {
+			chainID = ResolveChainID(chainID)
+			if util.ValidNumber(chainID) == nil {
+				return fmt.Errorf("invalid chain ID %s", chainID)
+			}
+			accountJSON.ChainIDs = append(accountJSON.ChainIDs, chainID)
+		}
+	}
+	if allowMessageSigning, ok := data.GetOk("allow_message_signing"); ok {
+		accountJSON.AllowMessageSigning = allowMessageSigning.(bool)
+	}
+	return nil
+}
+
 func (b *PluginBackend) pathAccountsRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	_, err := b.configured(ctx, req)
 	if err != nil {
@@ -339,6 +415,12 @@ func (b *PluginBackend) pathAccountsRead(ctx context.Context, req *logical.Reque
 			"address":    account.Address.Hex(),
 			"inclusions": accountJSON.Inclusions,
 			"exclusions": accountJSON.Exclusions,
+			"fee_policy": accountJSON.FeePolicy.Response(),
+
+			"bound_cidr_list": accountJSON.BoundCIDRList,
+			"chain_ids":       accountJSON.ChainIDs,
+
+			"allow_message_signing": accountJSON.AllowMessageSigning,
 		},
 	}, nil
 }
@@ -356,6 +438,15 @@ func (b *PluginBackend) pathAccountsDelete(ctx context.Context, req *logical.Req
 	if err := req.Storage.Delete(ctx, req.Path); err != nil {
 		return nil, err
 	}
+	// Inventory checkpoints are meaningless once the account is gone
+	inventory := logical.NewStorageView(req.Storage, QualifiedPath(fmt.Sprintf("inventory/%s/", name)))
+	if err := logical.ClearView(ctx, inventory); err != nil {
+		return nil, err
+	}
+	history := logical.NewStorageView(req.Storage, QualifiedPath(fmt.Sprintf("history/%s/", name)))
+	if err := logical.ClearView(ctx, history); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
@@ -387,6 +478,9 @@ func (b *PluginBackend) pathAccountsCreate(ctx context.Context, req *logical.Req
 	if exclusionsRaw, ok := data.GetOk("exclusions"); ok {
 		exclusions = exclusionsRaw.([]string)
 	}
+	if err := validAddressBookReferences(ctx, req.Storage, inclusions, exclusions); err != nil {
+		return nil, err
+	}
 	index := data.Get("index").(int)
 	mnemonic := data.Get("mnemonic").(string)
 	if mnemonic == Empty {
@@ -408,6 +502,12 @@ func (b *PluginBackend) pathAccountsCreate(ctx context.Context, req *logical.Req
 		Inclusions: util.Dedup(inclusions),
 		Exclusions: util.Dedup(exclusions),
 	}
+	if err := readFeePolicy(data, &accountJSON.FeePolicy); err != nil {
+		return nil, err
+	}
+	if err := readAccountRestrictions(data, accountJSON); err != nil {
+		return nil, err
+	}
 	_, account, err := getWalletAndAccount(*accountJSON)
 	if err != nil {
 		return nil, err
@@ -423,6 +523,12 @@ func (b *PluginBackend) pathAccountsCreate(ctx context.Context, req *logical.Req
 			"address":    account.Address.Hex(),
 			"inclusions": accountJSON.Inclusions,
 			"exclusions": accountJSON.Exclusions,
+			"fee_policy": accountJSON.FeePolicy.Response(),
+
+			"bound_cidr_list": accountJSON.BoundCIDRList,
+			"chain_ids":       accountJSON.ChainIDs,
+
+			"allow_message_signing": accountJSON.AllowMessageSigning,
 		},
 	}, nil
 }
@@ -461,8 +567,17 @@ func (b *PluginBackend) pathAccountUpdate(ctx context.Context, req *logical.Requ
 	if exclusionsRaw, ok := data.GetOk("exclusions"); ok {
 		exclusions = exclusionsRaw.([]string)
 	}
+	if err := validAddressBookReferences(ctx, req.Storage, inclusions, exclusions); err != nil {
+		return nil, err
+	}
 	accountJSON.Inclusions = inclusions
 	accountJSON.Exclusions = exclusions
+	if err := readFeePolicy(data, &accountJSON.FeePolicy); err != nil {
+		return nil, err
+	}
+	if err := readAccountRestrictions(data, accountJSON); err != nil {
+		return nil, err
+	}
 
 	err = b.updateAccount(ctx, req, name, accountJSON)
 	if err != nil {
@@ -478,6 +593,12 @@ func (b *PluginBackend) pathAccountUpdate(ctx context.Context, req *logical.Requ
 			"address":    account.Address.Hex(),
 			"inclusions": accountJSON.Inclusions,
 			"exclusions": accountJSON.Exclusions,
+			"fee_policy": accountJSON.FeePolicy.Response(),
+
+			"bound_cidr_list": accountJSON.BoundCIDRList,
+			"chain_ids":       accountJSON.ChainIDs,
+
+			"allow_message_signing": accountJSON.AllowMessageSigning,
 		},
 	}, nil
 
@@ -494,30 +615,31 @@ func pathExistenceCheck(ctx context.Context, req *logical.Request, data *framewo
 
 // returns (nonce, toAddress, amount, gasPrice, gasLimit, error)
 
-func (b *PluginBackend) getData(client *ethclient.Client, fromAddress common.Address, data *framework.FieldData) (*TransactionParams, error) {
-	transactionParams, err := b.getBaseData(client, fromAddress, data, "to")
+func (b *PluginBackend) getData(client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, fromAddress common.Address, data *framework.FieldData, txData []byte) (*TransactionParams, error) {
+	transactionParams, err := b.getBaseData(client, config, accountJSON, fromAddress, data, "to")
 	if err != nil {
 		return nil, err
 	}
-	var gasLimitIn *big.Int
-
-	gasLimitField, ok := data.GetOk("gas_limit")
 
-	if ok {
-		gasLimitIn = util.ValidNumber(gasLimitField.(string))
-	} else {
-		// if no gaslimit is provided, take default or zero value
-		gasLimitIn = util.ValidNumber(data.GetDefaultOrZero("gas_limit").(string))
+	// Without a gas limit, estimate it - calldata or a contract recipient needs more than 21000
+	gasLimit, err := b.gasLimit(context.Background(), client, config, accountJSON, data, ethereum.CallMsg{
+		From:     fromAddress,
+		To:       transactionParams.Address,
+		GasPrice: transactionParams.GasPrice,
+		Value:    transactionParams.Amount,
+		Data:     txData,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	gasLimit := gasLimitIn.Uint64()
-
 	return &TransactionParams{
-		Nonce:    transactionParams.Nonce,
-		Address:  transactionParams.Address,
-		Amount:   transactionParams.Amount,
-		GasPrice: transactionParams.GasPrice,
-		GasLimit: gasLimit,
+		Nonce:       transactionParams.Nonce,
+		Address:     transactionParams.Address,
+		AddressName: transactionParams.AddressName,
+		Amount:      transactionParams.Amount,
+		GasPrice:    transactionParams.GasPrice,
+		GasLimit:    gasLimit,
 	}, nil
 }
 
@@ -539,18 +661,17 @@ func (b *PluginBackend) NewWalletTransactor(chainID *big.Int, hdwallet *bip44.Wa
 	}, nil
 }
 
-func (b *PluginBackend) getBaseData(client *ethclient.Client, fromAddress common.Address, data *framework.FieldData, addressField string) (*TransactionParams, error) {
+func (b *PluginBackend) getBaseData(client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, fromAddress common.Address, data *framework.FieldData, addressField string) (*TransactionParams, error) {
 	var err error
-	var address common.Address
 	nonceData := "0"
 	var nonce uint64
 	var amount *big.Int
 	var gasPriceIn *big.Int
 	_, ok := data.GetOk("amount")
 	if ok {
-		amount = util.ValidNumber(data.Get("amount").(string))
-		if amount == nil {
-			return nil, fmt.Errorf("invalid amount")
+		amount, err = ParseAmount(data.Get("amount").(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount: %v", err)
 		}
 	} else {
 		amount = util.ValidNumber("0")
@@ -560,6 +681,9 @@ func (b *PluginBackend) getBaseData(client *ethclient.Client, fromAddress common
 	if ok {
 		nonceData = data.Get("nonce").(string)
 		nonceIn := util.ValidNumber(nonceData)
+		if nonceIn == nil || !nonceIn.IsUint64() {
+			return nil, fmt.Errorf("invalid nonce")
+		}
 		nonce = nonceIn.Uint64()
 	} else {
 		nonce, err = client.PendingNonceAt(context.Background(), fromAddress)
@@ -568,31 +692,23 @@ func (b *PluginBackend) getBaseData(client *ethclient.Client, fromAddress common
 		}
 	}
 
-	_, ok = data.GetOk("gas_price")
-	if ok {
-		gasPriceIn = util.ValidNumber(data.Get("gas_price").(string))
-		if gasPriceIn == nil {
-			return nil, fmt.Errorf("invalid gas price")
-		}
-	} else {
-		gasPriceIn = util.ValidNumber("0")
+	gasPriceIn, err = b.gasPrice(context.Background(), config, accountJSON, data)
+	if err != nil {
+		return nil, err
 	}
 
-	if big.NewInt(0).Cmp(gasPriceIn) == 0 {
-		gasPriceIn, err = client.SuggestGasPrice(context.Background())
+	if addressField != Empty {
+		address, ensName, err := b.resolveField(context.Background(), client, config, data, addressField)
 		if err != nil {
 			return nil, err
 		}
-	}
-
-	if addressField != Empty {
-		address = common.HexToAddress(data.Get(addressField).(string))
 		return &TransactionParams{
-			Nonce:    nonce,
-			Address:  &address,
-			Amount:   amount,
-			GasPrice: gasPriceIn,
-			GasLimit: 0,
+			Nonce:       nonce,
+			Address:     address,
+			AddressName: ensName,
+			Amount:      amount,
+			GasPrice:    gasPriceIn,
+			GasLimit:    0,
 		}, nil
 	}
 	return &TransactionParams{
@@ -607,7 +723,7 @@ func (b *PluginBackend) getBaseData(client *ethclient.Client, fromAddress common
 
 func (b *PluginBackend) pathTransfer(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	var txDataToSign []byte
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
@@ -617,12 +733,12 @@ func (b *PluginBackend) pathTransfer(ctx context.Context, req *logical.Request,
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to " + config.getRPCURL())
+		return nil, err
 	}
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -632,16 +748,11 @@ func (b *PluginBackend) pathTransfer(ctx context.Context, req *logical.Request,
 		return nil, err
 	}
 
-	transactionParams, err := b.getData(client, account.Address, data)
+	transactionParams, err := b.getData(client, config, accountJSON, account.Address, data, txDataToSign)
 
 	if err != nil {
 		return nil, err
 	}
-	accountJSON.Inclusions = append(accountJSON.Inclusions, config.Inclusions...)
-	accountJSON.Inclusions = append(accountJSON.Inclusions, accountJSON.Inclusions...)
-	if len(accountJSON.Inclusions) > 0 && !util.Contains(accountJSON.Inclusions, transactionParams.Address.Hex()) {
-		return nil, fmt.Errorf("%s violates the inclusions %+v", transactionParams.Address.Hex(), accountJSON.Inclusions)
-	}
 	err = config.ValidAddress(transactionParams.Address)
 	if err != nil {
 		return nil, err
@@ -656,15 +767,22 @@ func (b *PluginBackend) pathTransfer(ctx context.Context, req *logical.Request,
 	if err != nil {
 		return nil, err
 	}
+	l1DataFee, err := L1DataFee(ctx, client, config.ChainID, tx)
+	if err != nil {
+		return nil, err
+	}
 	err = client.SendTransaction(context.Background(), signedTx)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, signedTx, "transfer"); err != nil {
+		return nil, err
+	}
 
 	var signedTxBuff bytes.Buffer
 	signedTx.EncodeRLP(&signedTxBuff)
 
-	return &logical.Response{
+	return withL1DataFee(withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"transaction_hash":   signedTx.Hash().Hex(),
 			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
@@ -675,11 +793,11 @@ func (b *PluginBackend) pathTransfer(ctx context.Context, req *logical.Request,
 			"gas_price":          transactionParams.GasPrice.String(),
 			"gas_limit":          strconv.FormatUint(transactionParams.GasLimit, 10),
 		},
-	}, nil
+	}, "to", transactionParams.AddressName), tx, l1DataFee), nil
 }
 
 func (b *PluginBackend) pathDeploy(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
@@ -690,12 +808,12 @@ func (b *PluginBackend) pathDeploy(ctx context.Context, req *logical.Request, da
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to " + config.getRPCURL())
+		return nil, err
 	}
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -705,7 +823,7 @@ func (b *PluginBackend) pathDeploy(ctx context.Context, req *logical.Request, da
 		return nil, err
 	}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, Empty)
+	transactionParams, err := b.getBaseData(client, config, accountJSON, account.Address, data, Empty)
 
 	if err != nil {
 		return nil, err
@@ -726,13 +844,23 @@ func (b *PluginBackend) pathDeploy(ctx context.Context, req *logical.Request, da
 		return nil, err
 	}
 	gasLimitIn := util.ValidNumber(data.Get("gas_limit").(string))
+	if gasLimitIn == nil || !gasLimitIn.IsUint64() {
+		return nil, fmt.Errorf("invalid gas limit")
+	}
 	gasLimit := gasLimitIn.Uint64()
 
 	transactOpts.GasPrice = transactionParams.GasPrice
 	transactOpts.Nonce = big.NewInt(int64(transactionParams.Nonce))
 	transactOpts.Value = big.NewInt(0) // in wei
 
-	gasLimit, err = util.EstimateGas(transactOpts, parsed, binRaw, client)
+	estimated := gasLimit == 0
+	if estimated {
+		gasLimit, err = util.EstimateGas(transactOpts, parsed, binRaw, client)
+		if err != nil {
+			return nil, err
+		}
+	}
+	gasLimit, err = withGasPolicy(config, accountJSON, gasLimit, estimated)
 	if err != nil {
 		return nil, err
 	}
@@ -741,6 +869,9 @@ func (b *PluginBackend) pathDeploy(ctx context.Context, req *logical.Request, da
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, "deploy"); err != nil {
+		return nil, err
+	}
 	//	b.LogTx(tx)
 	var signedTxBuff bytes.Buffer
 	tx.EncodeRLP(&signedTxBuff)
@@ -760,13 +891,13 @@ func (b *PluginBackend) pathDeploy(ctx context.Context, req *logical.Request, da
 
 func (b *PluginBackend) pathSignTx(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	var txDataToSign []byte
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
-		return nil, fmt.Errorf("cannot connect to " + config.getRPCURL())
+		return nil, err
 	}
 
 	name := data.Get("name").(string)
@@ -787,7 +918,7 @@ func (b *PluginBackend) pathSignTx(ctx context.Context, req *logical.Request, da
 	} else {
 		return nil, fmt.Errorf("invalid encoding encountered - %s", encoding)
 	}
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -796,15 +927,11 @@ func (b *PluginBackend) pathSignTx(ctx context.Context, req *logical.Request, da
 	if err != nil {
 		return nil, err
 	}
-	transactionParams, err := b.getData(client, account.Address, data)
+	transactionParams, err := b.getData(client, config, accountJSON, account.Address, data, txDataToSign)
 	if err != nil {
 		return nil, err
 	}
 
-	accountJSON.Inclusions = append(accountJSON.Inclusions, config.Inclusions...)
-	if len(accountJSON.Inclusions) > 0 && !util.Contains(accountJSON.Inclusions, transactionParams.Address.Hex()) {
-		return nil, fmt.Errorf("%s violates the set of inclusions %+v", transactionParams.Address.Hex(), accountJSON.Inclusions)
-	}
 	err = config.ValidAddress(transactionParams.Address)
 	if err != nil {
 		return nil, err
@@ -820,10 +947,14 @@ func (b *PluginBackend) pathSignTx(ctx context.Context, req *logical.Request, da
 	if err != nil {
 		return nil, err
 	}
+	l1DataFee, err := L1DataFee(ctx, client, config.ChainID, tx)
+	if err != nil {
+		return nil, err
+	}
 	var signedTxBuff bytes.Buffer
 	signedTx.EncodeRLP(&signedTxBuff)
 
-	return &logical.Response{
+	return withL1DataFee(withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"transaction_hash":   signedTx.Hash().Hex(),
 			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
@@ -834,18 +965,18 @@ func (b *PluginBackend) pathSignTx(ctx context.Context, req *logical.Request, da
 			"gas_price":          transactionParams.GasPrice.String(),
 			"gas_limit":          strconv.FormatUint(transactionParams.GasLimit, 10),
 		},
-	}, nil
+	}, "to", transactionParams.AddressName), tx, l1DataFee), nil
 
 }
 
 func (b *PluginBackend) pathReadBalance(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 
 	name := data.Get("name").(string)
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -855,7 +986,7 @@ func (b *PluginBackend) pathReadBalance(ctx context.Context, req *logical.Reques
 		return nil, err
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -879,10 +1010,14 @@ func (b *PluginBackend) LogTx(tx *types.Transaction) {
 }
 
 func (b *PluginBackend) pathSignMessage(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
 	message := data.Get("message").(string)
 	name := data.Get("name").(string)
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
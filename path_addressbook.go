@@ -0,0 +1,298 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+const (
+	// AddressBookPrefix refers to an address book entry in place of an address
+	AddressBookPrefix string = "addressbook:"
+	// TagPrefix refers to every address book entry with a tag in inclusions and exclusions
+	TagPrefix string = "tag:"
+)
+
+// AddressBookEntryJSON is a named counterparty
+type AddressBookEntryJSON struct {
+	Address string   `json:"address"`
+	Label   string   `json:"label"`
+	Tags    []string `json:"tags"`
+}
+
+// addressBook holds the entries of the address book by name
+type addressBook map[string]*AddressBookEntryJSON
+
+// contains returns true if an inclusion or exclusion list names the address, either
+// directly, as addressbook:<name> or as tag:<tag>. Addresses are compared regardless of
+// their checksum case.
+func (book addressBook) contains(list []string, address *common.Address) bool {
+	for _, item := range list {
+		switch {
+		case strings.HasPrefix(item, AddressBookPrefix):
+			if entry, ok := book[strings.TrimPrefix(item, AddressBookPrefix)]; ok && strings.EqualFold(entry.Address, address.Hex()) {
+				return true
+			}
+		case strings.HasPrefix(item, TagPrefix):
+			tag := strings.TrimPrefix(item, TagPrefix)
+			for _, entry := range book {
+				if strings.EqualFold(entry.Address, address.Hex()) && util.Contains(entry.Tags, tag) {
+					return true
+				}
+			}
+		case strings.EqualFold(item, address.Hex()):
+			return true
+		}
+	}
+	return false
+}
+
+// unresolved returns the addressbook:<name> and tag:<tag> references in a list that no
+// entry answers to. An exclusion that refers to a deleted or retagged entry must not
+// quietly stop excluding anything.
+func (book addressBook) unresolved(list []string) []string {
+	var missing []string
+	for _, item := range list {
+		switch {
+		case strings.HasPrefix(item, AddressBookPrefix):
+			if _, ok := book[strings.TrimPrefix(item, AddressBookPrefix)]; !ok {
+				missing = append(missing, item)
+			}
+		case strings.HasPrefix(item, TagPrefix):
+			tag := strings.TrimPrefix(item, TagPrefix)
+			tagged := false
+			for _, entry := range book {
+				if util.Contains(entry.Tags, tag) {
+					tagged = true
+					break
+				}
+			}
+			if !tagged {
+				missing = append(missing, item)
+			}
+		}
+	}
+	return missing
+}
+
+// excludes returns an error if the exclusions name the address or refer to entries
+// that are no longer in the address book
+func (book addressBook) excludes(exclusions []string, address *common.Address, owner string) error {
+	if missing := book.unresolved(exclusions); len(missing) > 0 {
+		return fmt.Errorf("the exclusions of this %s refer to %s, which are not in the address book", owner, strings.Join(missing, ", "))
+	}
+	if book.contains(exclusions, address) {
+		return fmt.Errorf("%s is excludeded by this %s", address.Hex(), owner)
+	}
+	return nil
+}
+
+// validAddressBookReferences refuses inclusions and exclusions that refer to entries or
+// tags that aren't in the address book
+func validAddressBookReferences(ctx context.Context, s logical.Storage, inclusions []string, exclusions []string) error {
+	book, err := readAddressBook(ctx, s, inclusions, exclusions)
+	if err != nil {
+		return err
+	}
+	if missing := book.unresolved(inclusions); len(missing) > 0 {
+		return fmt.Errorf("inclusions refer to %s, which are not in the address book", strings.Join(missing, ", "))
+	}
+	if missing := book.unresolved(exclusions); len(missing) > 0 {
+		return fmt.Errorf("exclusions refer to %s, which are not in the address book", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// referencesAddressBook returns true if any of the lists refer to the address book
+func referencesAddressBook(lists ...[]string) bool {
+	for _, list := range lists {
+		for _, item := range list {
+			if strings.HasPrefix(item, AddressBookPrefix) || strings.HasPrefix(item, TagPrefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func addressBookPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: QualifiedPath("addressbook/?"),
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.pathAddressBookList,
+			},
+			HelpSynopsis: "List the entries of the address book",
+			HelpDescription: `
+			All the names in the address book will be listed.
+			`,
+		},
+		{
+			Pattern:      QualifiedPath("addressbook/" + framework.GenericNameRegex("name")),
+			HelpSynopsis: "Name a counterparty in the address book.",
+			HelpDescription: `
+
+Maps a name to an address, with a label and tags such as exchange, treasury or vendor.
+Address fields accept addressbook:<name> in place of an address, and inclusions and
+exclusions accept addressbook:<name> and tag:<tag>, so that changing an entry updates
+every policy that refers to it. References must resolve when a policy is written. If
+an entry that an exclusion refers to is later deleted or retagged, transactions under
+that policy are refused until the exclusion is fixed.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name": {Type: framework.TypeString},
+				"address": {
+					Type:        framework.TypeString,
+					Description: "The address of the counterparty.",
+				},
+				"label": {
+					Type:        framework.TypeString,
+					Description: "A description of the counterparty.",
+				},
+				"tags": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Tags that inclusions and exclusions can refer to as tag:<tag>.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathAddressBookRead,
+				logical.CreateOperation: b.pathAddressBookWrite,
+				logical.UpdateOperation: b.pathAddressBookWrite,
+				logical.DeleteOperation: b.pathAddressBookDelete,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathAddressBookList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	vals, err := req.Storage.List(ctx, QualifiedPath("addressbook/"))
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(vals), nil
+}
+
+func (b *PluginBackend) pathAddressBookRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	entry, err := readAddressBookEntry(ctx, req.Storage, data.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	return addressBookResponse(entry), nil
+}
+
+func (b *PluginBackend) pathAddressBookWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+	entry, err := readAddressBookEntry(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		entry = &AddressBookEntryJSON{}
+	}
+	if address, ok := data.GetOk("address"); ok {
+		parsed, err := util.ParseAddress(address.(string), false)
+		if err != nil {
+			return nil, err
+		}
+		entry.Address = parsed.Hex()
+	}
+	if label, ok := data.GetOk("label"); ok {
+		entry.Label = label.(string)
+	}
+	if tags, ok := data.GetOk("tags"); ok {
+		entry.Tags = util.Dedup(tags.([]string))
+	}
+	if entry.Address == Empty {
+		return nil, fmt.Errorf("address is required")
+	}
+
+	storageEntry, err := logical.StorageEntryJSON(QualifiedPath("addressbook/"+name), entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, storageEntry); err != nil {
+		return nil, err
+	}
+	return addressBookResponse(entry), nil
+}
+
+func (b *PluginBackend) pathAddressBookDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, QualifiedPath("addressbook/"+data.Get("name").(string))); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func addressBookResponse(entry *AddressBookEntryJSON) *logical.Response {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"address": entry.Address,
+			"label":   entry.Label,
+			"tags":    entry.Tags,
+		},
+	}
+}
+
+func readAddressBookEntry(ctx context.Context, s logical.Storage, name string) (*AddressBookEntryJSON, error) {
+	path := QualifiedPath("addressbook/" + name)
+	entry, err := s.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var addressBookEntry AddressBookEntryJSON
+	if err := entry.DecodeJSON(&addressBookEntry); err != nil {
+		return nil, fmt.Errorf("failed to deserialize address book entry at %s", path)
+	}
+	return &addressBookEntry, nil
+}
+
+// readAddressBook reads the whole address book if any of the lists refer to it, and
+// returns nil otherwise
+func readAddressBook(ctx context.Context, s logical.Storage, lists ...[]string) (addressBook, error) {
+	if !referencesAddressBook(lists...) {
+		return nil, nil
+	}
+	names, err := s.List(ctx, QualifiedPath("addressbook/"))
+	if err != nil {
+		return nil, err
+	}
+	book := make(addressBook)
+	for _, name := range names {
+		entry, err := readAddressBookEntry(ctx, s, name)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil {
+			book[name] = entry
+		}
+	}
+	return book, nil
+}
@@ -0,0 +1,173 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+// broadcastTransaction is a decoded raw transaction and the account that signed it
+type broadcastTransaction struct {
+	tx     *types.Transaction
+	from   common.Address
+	signer *signerAccount
+}
+
+func broadcastPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      QualifiedPath("broadcast"),
+			HelpSynopsis: "Send transactions that were signed earlier.",
+			HelpDescription: `
+
+Sends RLP encoded transactions, such as the signed_transaction returned by sign-tx,
+through the selected network's RPC endpoints. The sender of each transaction is
+recovered from its signature, and transactions that weren't signed by an account of
+this mount for this network's chain are refused. The account's restrictions,
+address lists and fee policy are checked as they are for sign-tx. Every transaction
+is checked before any is sent, and each one sent is recorded in the history of its
+account.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"network": networkField(),
+				"signed_transactions": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The hex encoded signed transactions, sent in order.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathBroadcast,
+				logical.UpdateOperation: b.pathBroadcast,
+			},
+		},
+	}
+}
+
+// decodeBroadcast decodes a raw transaction, recovers its sender and checks it against
+// the sender's account
+func (b *PluginBackend) decodeBroadcast(ctx context.Context, req *logical.Request, config *ConfigJSON, chainID *big.Int, raw string) (*broadcastTransaction, error) {
+	encoded, err := hexutil.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signed transaction: %v", err)
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encoded, tx); err != nil {
+		return nil, fmt.Errorf("invalid signed transaction: %v", err)
+	}
+	// Transactions without a chain ID could be replayed on any chain
+	if !tx.Protected() {
+		return nil, fmt.Errorf("transaction %s is not signed for a chain", tx.Hash().Hex())
+	}
+	from, err := types.Sender(types.NewEIP155Signer(chainID), tx)
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s is not signed for chain %s: %v", tx.Hash().Hex(), config.ChainID, err)
+	}
+	signer, err := b.signerAccountFor(ctx, req, config, from)
+	if err != nil {
+		return nil, err
+	}
+	if tx.To() != nil {
+		if err := config.notReserved(tx.To()); err != nil {
+			return nil, err
+		}
+		if err := config.ValidAddress(tx.To()); err != nil {
+			return nil, err
+		}
+		if err := signer.accountJSON.ValidAddress(tx.To()); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := withGasPolicy(config, signer.accountJSON, tx.Gas(), false); err != nil {
+		return nil, err
+	}
+	if _, err := b.policyGasPrice(ctx, config, signer.accountJSON, tx.GasPrice()); err != nil {
+		return nil, err
+	}
+	return &broadcastTransaction{tx: tx, from: from, signer: signer}, nil
+}
+
+func (b *PluginBackend) pathBroadcast(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	chainID := util.ValidNumber(config.ChainID)
+	if chainID == nil {
+		return nil, fmt.Errorf("invalid chain ID")
+	}
+	signedTransactions := data.Get("signed_transactions").([]string)
+	if len(signedTransactions) == 0 {
+		return nil, fmt.Errorf("signed_transactions is required")
+	}
+
+	seen := make(map[common.Hash]bool)
+	var broadcasts []*broadcastTransaction
+	for _, raw := range signedTransactions {
+		broadcast, err := b.decodeBroadcast(ctx, req, config, chainID, raw)
+		if err != nil {
+			return nil, err
+		}
+		if seen[broadcast.tx.Hash()] {
+			return nil, fmt.Errorf("transaction %s is included more than once", broadcast.tx.Hash().Hex())
+		}
+		seen[broadcast.tx.Hash()] = true
+		broadcasts = append(broadcasts, broadcast)
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	var sent []map[string]interface{}
+	for _, broadcast := range broadcasts {
+		tx := broadcast.tx
+		if err := client.SendTransaction(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to send %s after sending %d of %d transactions: %v", tx.Hash().Hex(), len(sent), len(broadcasts), err)
+		}
+		history, err := recordTransaction(ctx, req, config, broadcast.signer.name, broadcast.from, tx, "broadcast")
+		if err != nil {
+			return nil, err
+		}
+		sent = append(sent, map[string]interface{}{
+			"transaction_hash": history.TransactionHash,
+			"account":          broadcast.signer.name,
+			"from":             history.From,
+			"to":               history.To,
+			"amount":           history.Amount,
+			"nonce":            strconv.FormatUint(history.Nonce, 10),
+			"gas_price":        history.GasPrice,
+			"gas_limit":        strconv.FormatUint(history.GasLimit, 10),
+		})
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"network":      config.Network,
+			"transactions": sent,
+		},
+	}, nil
+}
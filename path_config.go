@@ -18,69 +18,86 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/immutability-io/vault-ethereum/util"
-
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/hashicorp/errwrap"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/helper/cidrutil"
 	"github.com/hashicorp/vault/sdk/logical"
-)
-
-const (
-	// EthereumMainnet Chain ID
-	EthereumMainnet string = "1"
-	// Morden Chain ID
-	Morden string = "2"
-	// Ropsten Chain ID
-	Ropsten string = "3"
-	// Rinkeby Chain ID
-	Rinkeby string = "4"
-	// RootstockMainnet Chain ID
-	RootstockMainnet string = "30"
-	// RootstockTestnet Chain ID
-	RootstockTestnet string = "31"
-	// Kovan Chain ID
-	Kovan string = "42"
-	// EthereumClassicMainnet Chain ID
-	EthereumClassicMainnet string = "61"
-	// EthereumClassicTestnet Chain ID
-	EthereumClassicTestnet string = "62"
-	// GethPrivateChains Chain ID
-	GethPrivateChains string = "1337"
-	// InfuraMainnet is the default for EthereumMainnet
-	InfuraMainnet string = "https://mainnet.infura.io"
-	// InfuraRopsten is the default for Ropsten
-	InfuraRopsten string = "https://ropsten.infura.io"
-	// InfuraKovan is the default for Kovan
-	InfuraKovan string = "https://kovan.infura.io"
-	// InfuraRinkeby is the default for Rinkeby
-	InfuraRinkeby string = "https://rinkeby.infura.io"
-	// Local is the default for localhost
-	Local string = "http://localhost:8545"
+	"github.com/immutability-io/vault-ethereum/util"
 )
 
 // ConfigJSON contains the configuration for each mount
 type ConfigJSON struct {
-	BoundCIDRList []string `json:"bound_cidr_list_list" structs:"bound_cidr_list" mapstructure:"bound_cidr_list"`
-	Inclusions    []string `json:"inclusions"`
-	Exclusions    []string `json:"exclusions"`
-	RPC           string   `json:"rpc_url"`
-	ChainID       string   `json:"chain_id"`
+	BoundCIDRList  []string      `json:"bound_cidr_list_list" structs:"bound_cidr_list" mapstructure:"bound_cidr_list"`
+	Inclusions     []string      `json:"inclusions"`
+	Exclusions     []string      `json:"exclusions"`
+	RPC            string        `json:"rpc_url"`
+	ChainID        string        `json:"chain_id"`
+	ENSRegistry    string        `json:"ens_registry"`
+	BundlerURL     string        `json:"bundler_url"`
+	DefaultNetwork string        `json:"default_network"`
+	RPCAuth        RPCAuthJSON   `json:"rpc_auth"`
+	FeePolicy      FeePolicyJSON `json:"fee_policy"`
+	// PriceOracle is where convert gets the price of the native asset
+	PriceOracle PriceOracleJSON `json:"price_oracle"`
+	// RPCAllowlist is the JSON-RPC methods the rpc path forwards to the node
+	RPCAllowlist []string `json:"rpc_allowlist"`
+	// RequireChecksum refuses addresses without an EIP-55 checksum
+	RequireChecksum bool `json:"require_checksum"`
+	// AllowReservedAddresses allows the zero address and the precompiles as addresses
+	AllowReservedAddresses bool `json:"allow_reserved_addresses"`
+	// Network is the name of the network selected for a request
+	Network     string `json:"-"`
+	network     *NetworkJSON
+	addressBook addressBook
+	storage     logical.Storage
 }
 
 // ValidAddress returns an error if the address is not included or if it is excluded
 func (config *ConfigJSON) ValidAddress(toAddress *common.Address) error {
-	if util.Contains(config.Exclusions, toAddress.Hex()) {
-		return fmt.Errorf("%s is excludeded by this mount", toAddress.Hex())
+	if err := config.addressBook.excludes(config.Exclusions, toAddress, "mount"); err != nil {
+		return err
 	}
 
-	if len(config.Inclusions) > 0 && !util.Contains(config.Inclusions, toAddress.Hex()) {
+	if len(config.Inclusions) > 0 && !config.addressBook.contains(config.Inclusions, toAddress) {
 		return fmt.Errorf("%s is not in the set of inclusions of this mount", toAddress.Hex())
 	}
+	if config.network != nil {
+		return config.network.ValidAddress(toAddress)
+	}
+	return nil
+}
+
+// parseAddress parses an address parameter strictly, refusing reserved addresses
+func (config *ConfigJSON) parseAddress(input string) (*common.Address, error) {
+	address, err := util.ParseAddress(input, config.RequireChecksum)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.notReserved(&address); err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// notReserved returns an error for the zero address and the precompiles, which are
+// almost always a typo and would burn whatever is sent to them
+func (config *ConfigJSON) notReserved(address *common.Address) error {
+	if !config.AllowReservedAddresses && util.IsReservedAddress(*address) {
+		return fmt.Errorf("%s is the zero address or a precompile - set allow_reserved_addresses to use it", address.Hex())
+	}
 	return nil
 }
 
+// addressField parses an address field on a request
+func (config *ConfigJSON) addressField(data *framework.FieldData, field string) (common.Address, error) {
+	address, err := config.parseAddress(data.Get(field).(string))
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid %s: %v", field, err)
+	}
+	return *address, nil
+}
+
 func configPaths(b *PluginBackend) []*framework.Path {
 	return []*framework.Path{
 		{
@@ -94,28 +111,34 @@ func configPaths(b *PluginBackend) []*framework.Path {
 			HelpDescription: `
 			Configure the Vault Ethereum plugin.
 			`,
-			Fields: map[string]*framework.FieldSchema{
+			Fields: withPriceOracleFields(withFeePolicyFields(withRPCAuthFields(map[string]*framework.FieldSchema{
 				"chain_id": {
-					Type: framework.TypeString,
-					Description: `Ethereum network - can be one of the following values:
-
-					1 - Ethereum mainnet
-					2 - Morden (disused), Expanse mainnet
-					3 - Ropsten
-					4 - Rinkeby
-					30 - Rootstock mainnet
-					31 - Rootstock testnet
-					42 - Kovan
-					61 - Ethereum Classic mainnet
-					62 - Ethereum Classic testnet
-					1337 - Geth private chains (default)`,
-					Default: Rinkeby,
+					Type:        framework.TypeString,
+					Description: chainIDDescription(),
+				},
+				"force": {
+					Type:        framework.TypeBool,
+					Default:     false,
+					Description: "Save the configuration even if the node can't be reached or reports a different chain ID",
 				},
 				"rpc_url": {
 					Type:        framework.TypeString,
-					Default:     InfuraRinkeby,
+					Default:     Local,
 					Description: "The RPC address of the Ethereum network",
 				},
+				"ens_registry": {
+					Type:        framework.TypeString,
+					Default:     ENSRegistry,
+					Description: "The address of the ENS registry used to resolve names given in place of addresses",
+				},
+				"bundler_url": {
+					Type:        framework.TypeString,
+					Description: "The RPC address of an ERC-4337 bundler that user operations are submitted to",
+				},
+				"default_network": {
+					Type:        framework.TypeString,
+					Description: "The named network used by requests that don't give one - if unset, rpc_url and chain_id are used",
+				},
 				"inclusions": {
 					Type:        framework.TypeCommaStringSlice,
 					Description: "Only these accounts may be transaction with",
@@ -124,24 +147,67 @@ func configPaths(b *PluginBackend) []*framework.Path {
 					Type:        framework.TypeCommaStringSlice,
 					Description: "These accounts can never be transacted with",
 				},
+				"rpc_allowlist": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The read-only JSON-RPC methods the rpc path forwards to the node, such as eth_getBlockByNumber,eth_call",
+				},
+				"require_checksum": {
+					Type:        framework.TypeBool,
+					Default:     false,
+					Description: "Refuse addresses that aren't EIP-55 checksummed - mixed case addresses are always checked",
+				},
+				"allow_reserved_addresses": {
+					Type:        framework.TypeBool,
+					Default:     false,
+					Description: "Allow the zero address and the precompiles as addresses",
+				},
 				"bound_cidr_list": {
 					Type: framework.TypeCommaStringSlice,
 					Description: `Comma separated string or list of CIDR blocks.
 If set, specifies the blocks of IPs which can perform the login operation;
 if unset, there are no IP restrictions.`,
 				},
-			},
+			}))),
 		},
 	}
 }
 
-func (config *ConfigJSON) getRPCURL() string {
-	return config.RPC
-}
-
 func (b *PluginBackend) pathWriteConfig(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	rpcURL := data.Get("rpc_url").(string)
-	chainID := data.Get("chain_id").(string)
+	var rpcAuth RPCAuthJSON
+	if err := readRPCAuth(data, &rpcAuth); err != nil {
+		return nil, err
+	}
+	rpcURL, err := withoutCredentials(data.Get("rpc_url").(string), &rpcAuth)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := verifyChainID(ctx, ResolveChainID(data.Get("chain_id").(string)), []string{rpcURL}, &rpcAuth, data.Get("force").(bool))
+	if err != nil {
+		return nil, err
+	}
+	var feePolicy FeePolicyJSON
+	if err := readFeePolicy(data, &feePolicy); err != nil {
+		return nil, err
+	}
+	var priceOracle PriceOracleJSON
+	if err := readPriceOracle(data, &priceOracle); err != nil {
+		return nil, err
+	}
+	ensRegistry := data.Get("ens_registry").(string)
+	if _, err := util.ParseAddress(ensRegistry, false); err != nil {
+		return nil, fmt.Errorf("invalid ens_registry: %v", err)
+	}
+	bundlerURL := data.Get("bundler_url").(string)
+	defaultNetwork := data.Get("default_network").(string)
+	if defaultNetwork != Empty {
+		network, err := readNetwork(ctx, req.Storage, defaultNetwork)
+		if err != nil {
+			return nil, err
+		}
+		if network == nil {
+			return nil, fmt.Errorf("network %s does not exist", defaultNetwork)
+		}
+	}
 	var boundCIDRList []string
 	if boundCIDRListRaw, ok := data.GetOk("bound_cidr_list"); ok {
 		boundCIDRList = boundCIDRListRaw.([]string)
@@ -154,12 +220,32 @@ func (b *PluginBackend) pathWriteConfig(ctx context.Context, req *logical.Reques
 	if exclusionsRaw, ok := data.GetOk("exclusions"); ok {
 		exclusions = exclusionsRaw.([]string)
 	}
+	if err := validAddressBookReferences(ctx, req.Storage, inclusions, exclusions); err != nil {
+		return nil, err
+	}
+	var rpcAllowlist []string
+	if rpcAllowlistRaw, ok := data.GetOk("rpc_allowlist"); ok {
+		rpcAllowlist = util.Dedup(rpcAllowlistRaw.([]string))
+	}
+	if err := validRPCAllowlist(rpcAllowlist); err != nil {
+		return nil, err
+	}
 	configBundle := ConfigJSON{
-		BoundCIDRList: boundCIDRList,
-		Inclusions:    inclusions,
-		Exclusions:    exclusions,
-		ChainID:       chainID,
-		RPC:           rpcURL,
+		BoundCIDRList:  boundCIDRList,
+		Inclusions:     inclusions,
+		Exclusions:     exclusions,
+		ChainID:        chainID,
+		RPC:            rpcURL,
+		ENSRegistry:    ensRegistry,
+		BundlerURL:     bundlerURL,
+		DefaultNetwork: defaultNetwork,
+		RPCAuth:        rpcAuth,
+		FeePolicy:      feePolicy,
+		PriceOracle:    priceOracle,
+		RPCAllowlist:   rpcAllowlist,
+
+		RequireChecksum:        data.Get("require_checksum").(bool),
+		AllowReservedAddresses: data.Get("allow_reserved_addresses").(bool),
 	}
 	entry, err := logical.StorageEntryJSON("config", configBundle)
 
@@ -170,6 +256,7 @@ func (b *PluginBackend) pathWriteConfig(ctx context.Context, req *logical.Reques
 	if err := req.Storage.Put(ctx, entry); err != nil {
 		return nil, err
 	}
+	b.resetClients(ctx)
 	// Return the secret
 	return &logical.Response{
 		Data: map[string]interface{}{
@@ -178,6 +265,17 @@ func (b *PluginBackend) pathWriteConfig(ctx context.Context, req *logical.Reques
 			"exclusions":      configBundle.Exclusions,
 			"rpc_url":         configBundle.RPC,
 			"chain_id":        configBundle.ChainID,
+			"chain":           chainResponse(configBundle.ChainID),
+			"ens_registry":    configBundle.ENSRegistry,
+			"bundler_url":     configBundle.BundlerURL,
+			"default_network": configBundle.DefaultNetwork,
+			"rpc_auth":        configBundle.RPCAuth.Methods(),
+			"fee_policy":      configBundle.FeePolicy.Response(),
+			"price_oracle":    configBundle.PriceOracle.Response(),
+			"rpc_allowlist":   configBundle.RPCAllowlist,
+
+			"require_checksum":         configBundle.RequireChecksum,
+			"allow_reserved_addresses": configBundle.AllowReservedAddresses,
 		},
 	}, nil
 }
@@ -200,6 +298,17 @@ func (b *PluginBackend) pathReadConfig(ctx context.Context, req *logical.Request
 			"exclusions":      configBundle.Exclusions,
 			"rpc_url":         configBundle.RPC,
 			"chain_id":        configBundle.ChainID,
+			"chain":           chainResponse(configBundle.ChainID),
+			"ens_registry":    configBundle.ENSRegistry,
+			"bundler_url":     configBundle.BundlerURL,
+			"default_network": configBundle.DefaultNetwork,
+			"rpc_auth":        configBundle.RPCAuth.Methods(),
+			"fee_policy":      configBundle.FeePolicy.Response(),
+			"price_oracle":    configBundle.PriceOracle.Response(),
+			"rpc_allowlist":   configBundle.RPCAllowlist,
+
+			"require_checksum":         configBundle.RequireChecksum,
+			"allow_reserved_addresses": configBundle.AllowReservedAddresses,
 		},
 	}, nil
 }
@@ -233,22 +342,32 @@ func (b *PluginBackend) configured(ctx context.Context, req *logical.Request) (*
 	if validConnection, err := b.validIPConstraints(config, req); !validConnection {
 		return nil, err
 	}
+	config.addressBook, err = readAddressBook(ctx, req.Storage, config.Inclusions, config.Exclusions)
+	if err != nil {
+		return nil, err
+	}
+	config.storage = req.Storage
 
 	return config, nil
 }
 
 func (b *PluginBackend) validIPConstraints(config *ConfigJSON, req *logical.Request) (bool, error) {
-	if len(config.BoundCIDRList) != 0 {
+	return validSourceAddress(req, config.BoundCIDRList, "the role")
+}
+
+// validSourceAddress checks that the request comes from one of the CIDR blocks, if any
+func validSourceAddress(req *logical.Request, boundCIDRList []string, restricted string) (bool, error) {
+	if len(boundCIDRList) != 0 {
 		if req.Connection == nil || req.Connection.RemoteAddr == "" {
 			return false, fmt.Errorf("failed to get connection information")
 		}
 
-		belongs, err := cidrutil.IPBelongsToCIDRBlocksSlice(req.Connection.RemoteAddr, config.BoundCIDRList)
+		belongs, err := cidrutil.IPBelongsToCIDRBlocksSlice(req.Connection.RemoteAddr, boundCIDRList)
 		if err != nil {
-			return false, errwrap.Wrapf("failed to verify the CIDR restrictions set on the role: {{err}}", err)
+			return false, errwrap.Wrapf(fmt.Sprintf("failed to verify the CIDR restrictions set on %s: {{err}}", restricted), err)
 		}
 		if !belongs {
-			return false, fmt.Errorf("source address %q unauthorized through CIDR restrictions on the role", req.Connection.RemoteAddr)
+			return false, fmt.Errorf("source address %q unauthorized through CIDR restrictions on %s", req.Connection.RemoteAddr, restricted)
 		}
 	}
 	return true, nil
@@ -17,15 +17,15 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
-	"net/http"
+	"math/big"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/util"
 	"github.com/shopspring/decimal"
-	coingecko "github.com/superoo7/go-gecko/v3"
 )
 
 const (
@@ -54,34 +54,50 @@ const (
 
 	// USD is US Dollar
 	USD string = "usd"
+	// EUR is Euro
+	EUR string = "eur"
+	// GBP is Pound Sterling
+	GBP string = "gbp"
 	// AED is United Arab Emirates Dirham
 	AED string = "aed"
 	// ARS is Argentine Peso
 	ARS string = "ars"
+
+	// ConvertPrecision is the number of decimal places kept when dividing by a price
+	ConvertPrecision int32 = 36
 )
 
+// amountPattern matches a decimal amount with an optional unit, such as 1.5 ether or 30gwei
+var amountPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
+
 func convertPaths(b *PluginBackend) []*framework.Path {
 	return []*framework.Path{
 		{
 			Pattern:      "convert",
-			HelpSynopsis: "Convert any Ethereum unit to another.",
+			HelpSynopsis: "Convert between Ethereum units, fiat currencies and tokens.",
 			HelpDescription: `
 
-			Convert any Ethereum unit to another.
+			Convert any Ethereum unit, fiat currency or registered ERC-20 token to another,
+			such as 250 USDC to ether or 0.3 ether to EUR. Conversions other than between
+			Ethereum units use the price source configured on the mount, and report the
+			prices, their source and when they were last updated. Unlike the plain unit
+			conversion it grew from, it needs a Vault token, since it reads the mount's
+			tokens and calls its RPC endpoints.
 `,
 			Fields: map[string]*framework.FieldSchema{
 				"unit_from": {
 					Type:        framework.TypeString,
-					Description: "The Ethereum unit to convert from.",
+					Description: "The Ethereum unit, fiat currency or token symbol to convert from.",
 				},
 				"unit_to": {
 					Type:        framework.TypeString,
-					Description: "The Ethereum unit to convert to.",
+					Description: "The Ethereum unit, fiat currency or token symbol to convert to.",
 				},
 				"amount": {
 					Type:        framework.TypeString,
 					Description: "Amount to convert.",
 				},
+				"network": networkField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -136,8 +152,6 @@ func ValidUnit(unit string) (string, error) {
 		return GIGA, nil
 	case "tera", "teraether", "tether":
 		return TERA, nil
-	case "usd", "USD":
-		return USD, nil
 	}
 	return "", fmt.Errorf("Unknown unit %s", unit)
 }
@@ -216,94 +230,254 @@ func ConvertFromWei(normalizeUnit string, amount decimal.Decimal) decimal.Decima
 	return result
 }
 
-// ConvertToUSD uses Coinmarketcap to estimate value of ETH in USD
-func ConvertToUSD(amountInWei string) (decimal.Decimal, error) {
-	zero, _ := decimal.NewFromString("0")
-	httpClient := &http.Client{
-		Timeout: time.Second * 10,
-	}
-	client := coingecko.NewClient(httpClient)
-	singlePrice, err := client.SimpleSinglePrice("ethereum", "usd")
-	if err != nil {
-		log.Fatal(err)
+// ParseAmount parses an amount of ether given as wei, as 0x hex wei or as a number with
+// a unit, such as 1.5 ether or 30 gwei
+func ParseAmount(input string) (*big.Int, error) {
+	input = strings.TrimSpace(input)
+	if input == Empty {
+		return big.NewInt(0), nil
+	}
+	if strings.HasPrefix(input, "0x") || strings.HasPrefix(input, "0X") {
+		amount := util.ValidNumber(input)
+		if amount == nil {
+			return nil, fmt.Errorf("%q is not a valid hex amount", input)
+		}
+		return amount, nil
+	}
+	matches := amountPattern.FindStringSubmatch(input)
+	if matches == nil {
+		return nil, fmt.Errorf("%q is not a valid amount - give wei, 0x hex or a number and a unit such as 1.5 ether", input)
+	}
+	unit := WEI
+	if matches[2] != Empty {
+		var err error
+		unit, err = ValidUnit(matches[2])
+		if err != nil {
+			return nil, err
+		}
 	}
-	balanceInWei, err := decimal.NewFromString(amountInWei)
+	amount, err := decimal.NewFromString(matches[1])
 	if err != nil {
-		return zero, err
+		return nil, err
 	}
-	price := decimal.NewFromFloat32(singlePrice.MarketPrice)
-	balanceInETH := ConvertFromWei(ETH, balanceInWei)
-	exchangeValue := price.Mul(balanceInETH)
-	return exchangeValue, nil
-
+	return wholeAmount(input, ConvertToWei(unit, amount))
 }
 
-func (b *PluginBackend) pathConvertWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	_, err := b.configured(ctx, req)
+// ParseTokenAmount parses an amount of a token with the given decimals, such as 1.5, or
+// an amount in the token's base units given as 0x hex
+func ParseTokenAmount(input string, decimals uint8) (*big.Int, error) {
+	input = strings.TrimSpace(input)
+	if input == Empty {
+		return big.NewInt(0), nil
+	}
+	if strings.HasPrefix(input, "0x") || strings.HasPrefix(input, "0X") {
+		amount := util.ValidNumber(input)
+		if amount == nil {
+			return nil, fmt.Errorf("%q is not a valid hex amount", input)
+		}
+		return amount, nil
+	}
+	matches := amountPattern.FindStringSubmatch(input)
+	if matches == nil || matches[2] != Empty {
+		return nil, fmt.Errorf("%q is not a valid number of tokens", input)
+	}
+	amount, err := decimal.NewFromString(matches[1])
 	if err != nil {
 		return nil, err
 	}
+	return wholeAmount(input, amount.Shift(int32(decimals)))
+}
 
-	usdFrom := false
-	usdTo := false
-	exchangeValue, _ := decimal.NewFromString("0")
-	amountFromInUnits, _ := decimal.NewFromString("0")
-	oneETH, _ := decimal.NewFromString("1")
-	unitFrom, err := ValidUnit(data.Get("unit_from").(string))
-	if err != nil {
-		return nil, err
+// wholeAmount refuses amounts that are fractions of the smallest unit or that don't fit in
+// 256 bits
+func wholeAmount(input string, amount decimal.Decimal) (*big.Int, error) {
+	if !amount.IsInteger() {
+		return nil, fmt.Errorf("%s is more precise than the smallest unit", input)
 	}
-	amountFrom := data.Get("amount").(string)
-	amount, err := decimal.NewFromString(amountFrom)
-	if err != nil || amount.IsNegative() {
-		return nil, fmt.Errorf("amount is either not a number or is negative")
+	result := amount.BigInt()
+	if result.BitLen() > 256 {
+		return nil, fmt.Errorf("%s is too large", input)
+	}
+	return result, nil
+}
+
+// convertUnit is a unit convert accepts: an ether unit, a fiat currency or a registered token
+type convertUnit struct {
+	Name     string
+	Ether    string
+	Currency string
+	Token    *TokenJSON
+}
+
+// places is the number of decimal places of the smallest amount of the unit, or -1 if
+// amounts in the unit aren't rounded
+func (unit *convertUnit) places() int32 {
+	switch {
+	case unit.Ether != Empty:
+		return int32(len(ToWeiMultiplier(unit.Ether).String()) - 1)
+	case unit.Token != nil:
+		return int32(unit.Token.Decimals)
 	}
+	return -1
+}
 
-	unitTo, err := ValidUnit(data.Get("unit_to").(string))
+// validConvertUnit resolves an ether unit, one of the mount's fiat currencies or the
+// symbol of a registered token, in that order
+func validConvertUnit(ctx context.Context, req *logical.Request, config *ConfigJSON, unit string) (*convertUnit, error) {
+	if etherUnit, err := ValidUnit(unit); err == nil {
+		return &convertUnit{Name: etherUnit, Ether: etherUnit}, nil
+	}
+	name := strings.ToLower(unit)
+	if util.Contains(config.PriceOracle.currencies(), name) {
+		return &convertUnit{Name: name, Currency: name}, nil
+	}
+	token, err := readToken(ctx, req.Storage, name)
 	if err != nil {
 		return nil, err
 	}
-	if unitFrom == unitTo {
-		return nil, fmt.Errorf("Conversion from %s to %s makes no sense", unitFrom, unitTo)
+	if token != nil {
+		// The decimals and price feeds of a token are those of the chain it was registered on
+		if token.ChainID != config.ChainID {
+			return nil, fmt.Errorf("token %s is registered on chain %s, not %s", name, token.ChainID, config.ChainID)
+		}
+		return &convertUnit{Name: name, Token: token}, nil
+	}
+	return nil, fmt.Errorf("Unknown unit %s", unit)
+}
+
+// converter prices units in a currency and remembers the prices it used
+type converter struct {
+	b      *PluginBackend
+	config *ConfigJSON
+	prices []*Price
+}
+
+func (c *converter) price(ctx context.Context, asset *Asset, currency string) (decimal.Decimal, error) {
+	price, err := c.b.price(ctx, c.config, asset, currency)
+	if err != nil {
+		return decimal.Zero, err
 	}
-	if unitFrom == USD || unitTo == USD {
-		oneETHInWei := ConvertToWei(ETH, oneETH)
-		exchangeValue, err = ConvertToUSD(oneETHInWei.String())
+	c.prices = append(c.prices, price)
+	return price.Value, nil
+}
+
+// unitPrice returns the price of one of a unit in a currency. The exchange rate between
+// two currencies is taken from the price of the native asset in each of them.
+func (c *converter) unitPrice(ctx context.Context, unit *convertUnit, currency string) (decimal.Decimal, error) {
+	switch {
+	case unit.Ether != Empty:
+		price, err := c.price(ctx, c.config.nativeAsset(), currency)
 		if err != nil {
-			return nil, err
+			return decimal.Zero, err
 		}
-	}
-	if unitFrom == USD {
-		usdFrom = true
-		unitFrom = ETH
-		amount = amount.Div(exchangeValue)
+		return price.Mul(ConvertFromWei(ETH, ToWeiMultiplier(unit.Ether))), nil
+	case unit.Currency == currency:
+		return decimal.New(1, 0), nil
+	case unit.Currency != Empty:
+		price, err := c.price(ctx, c.config.nativeAsset(), currency)
 		if err != nil {
-			return nil, err
+			return decimal.Zero, err
+		}
+		unitPrice, err := c.price(ctx, c.config.nativeAsset(), unit.Currency)
+		if err != nil {
+			return decimal.Zero, err
 		}
+		return price.DivRound(unitPrice, ConvertPrecision), nil
+	}
+	return c.price(ctx, unit.Token.asset(unit.Name), currency)
+}
+
+// convert converts an amount between any two units. Ether units are converted exactly;
+// anything else is priced in a currency: the fiat side of the conversion if there is
+// one, and otherwise the first of the mount's currencies.
+func (c *converter) convert(ctx context.Context, from *convertUnit, to *convertUnit, amount decimal.Decimal) (decimal.Decimal, error) {
+	if from.Ether != Empty && to.Ether != Empty {
+		return ConvertFromWei(to.Ether, ConvertToWei(from.Ether, amount)), nil
+	}
+	currency := c.config.PriceOracle.currencies()[0]
+	if from.Currency != Empty {
+		currency = from.Currency
+	} else if to.Currency != Empty {
+		currency = to.Currency
+	}
+	fromPrice, err := c.unitPrice(ctx, from, currency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	toPrice, err := c.unitPrice(ctx, to, currency)
+	if err != nil {
+		return decimal.Zero, err
 	}
-	amountFromInWei := ConvertToWei(unitFrom, amount)
-	if unitTo == USD {
-		usdTo = true
-		ethInWei := ConvertFromWei(ETH, amountFromInWei)
-		amountFromInUnits = ethInWei.Mul(exchangeValue)
-	} else {
-		amountFromInUnits = ConvertFromWei(unitTo, amountFromInWei)
+	result := amount.Mul(fromPrice).DivRound(toPrice, ConvertPrecision)
+	if places := to.places(); places >= 0 {
+		result = result.Round(places)
 	}
+	return result, nil
+}
 
-	if usdFrom {
-		unitFrom = data.Get("unit_from").(string)
-		amount, _ = decimal.NewFromString(data.Get("amount").(string))
-	} else if usdTo {
-		unitTo = data.Get("unit_to").(string)
+func (b *PluginBackend) pathConvertWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
 	}
-	return &logical.Response{
+
+	unitFrom, err := validConvertUnit(ctx, req, config, data.Get("unit_from").(string))
+	if err != nil {
+		return nil, err
+	}
+	amount, err := decimal.NewFromString(data.Get("amount").(string))
+	if err != nil || amount.IsNegative() {
+		return nil, fmt.Errorf("amount is either not a number or is negative")
+	}
+	unitTo, err := validConvertUnit(ctx, req, config, data.Get("unit_to").(string))
+	if err != nil {
+		return nil, err
+	}
+	if unitFrom.Name == unitTo.Name {
+		return nil, fmt.Errorf("Conversion from %s to %s makes no sense", unitFrom.Name, unitTo.Name)
+	}
+
+	c := &converter{b: b, config: config}
+	amountTo, err := c.convert(ctx, unitFrom, unitTo, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &logical.Response{
 		Data: map[string]interface{}{
-			"unit_from":   unitFrom,
+			"unit_from":   unitFrom.Name,
 			"amount_from": amount,
-			"unit_to":     unitTo,
-			"amount_to":   amountFromInUnits.String(),
+			"unit_to":     unitTo.Name,
+			"amount_to":   amountTo.String(),
 		},
-	}, nil
+	}
+	if unitFrom.Token != nil {
+		response.Data["amount_from_base_units"] = amount.Shift(int32(unitFrom.Token.Decimals)).Truncate(0).String()
+		response.Data["token_from"] = unitFrom.Token.Address
+	}
+	if unitTo.Token != nil {
+		response.Data["amount_to_base_units"] = amountTo.Shift(int32(unitTo.Token.Decimals)).Truncate(0).String()
+		response.Data["token_to"] = unitTo.Token.Address
+	}
+	if len(c.prices) > 0 {
+		var prices []map[string]interface{}
+		oldest := c.prices[0].Timestamp
+		for _, price := range c.prices {
+			if price.Timestamp.Before(oldest) {
+				oldest = price.Timestamp
+			}
+			prices = append(prices, map[string]interface{}{
+				"asset":     price.Asset,
+				"currency":  price.Currency,
+				"price":     price.Value.String(),
+				"timestamp": price.Timestamp.UTC().Format(time.RFC3339),
+			})
+		}
+		response.Data["prices"] = prices
+		response.Data["price_source"] = c.prices[0].Source
+		response.Data["price_timestamp"] = oldest.UTC().Format(time.RFC3339)
+	}
+	return response, nil
 }
 
 func (b *PluginBackend) pathTest(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
@@ -0,0 +1,118 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// maxUint256 is 2^256 - 1, the largest amount that fits in a transaction
+const maxUint256 = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: "0"},
+		{input: "  ", want: "0"},
+		{input: "0", want: "0"},
+		{input: "1000", want: "1000"},
+		{input: "1000 wei", want: "1000"},
+		{input: "1.5 ether", want: "1500000000000000000"},
+		{input: "1.5ether", want: "1500000000000000000"},
+		{input: "30 gwei", want: "30000000000"},
+		{input: "0.000000000000000001 ether", want: "1"},
+		{input: "0xde0b6b3a7640000", want: "1000000000000000000"},
+		{input: "0x" + strings.Repeat("f", 64), want: maxUint256},
+		{input: maxUint256, want: maxUint256},
+		// Too many decimals for the unit
+		{input: "0.0000000000000000001 ether"},
+		{input: "1.5 wei"},
+		// Overflow past 2^256
+		{input: "115792089237316195423570985008687907853269984665640564039457584007913129639936"},
+		{input: "0x1" + strings.Repeat("0", 64)},
+		{input: "1000000000000000000000000000000000000000000000000000000000000000 ether"},
+		// Negative and malformed
+		{input: "-1"},
+		{input: "-1 ether"},
+		{input: "-0x1"},
+		{input: "1e18"},
+		{input: "1.5 bananas"},
+		{input: "0x"},
+		{input: "0xzz"},
+		{input: ".5 ether"},
+	}
+	for _, test := range tests {
+		got, err := ParseAmount(test.input)
+		if test.want == Empty {
+			if err == nil {
+				t.Errorf("ParseAmount(%q) = %s, want an error", test.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAmount(%q) failed: %v", test.input, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("ParseAmount(%q) = %s, want %s", test.input, got, test.want)
+		}
+	}
+}
+
+func TestParseTokenAmount(t *testing.T) {
+	tests := []struct {
+		input    string
+		decimals uint8
+		want     string
+	}{
+		{input: "", decimals: 18, want: "0"},
+		{input: "1", decimals: 18, want: "1000000000000000000"},
+		{input: "1.5", decimals: 6, want: "1500000"},
+		{input: "0.000001", decimals: 6, want: "1"},
+		{input: "42", decimals: 0, want: "42"},
+		{input: "0x10", decimals: 18, want: "16"},
+		{input: "0x" + strings.Repeat("f", 64), decimals: 6, want: maxUint256},
+		// Too many decimals for the token
+		{input: "0.0000001", decimals: 6},
+		{input: "0.5", decimals: 0},
+		// Overflow past 2^256
+		{input: maxUint256, decimals: 1},
+		{input: "0x1" + strings.Repeat("0", 64), decimals: 6},
+		// Negative and malformed
+		{input: "-1", decimals: 18},
+		{input: "1 ether", decimals: 18},
+		{input: "1e6", decimals: 6},
+		{input: "0x", decimals: 6},
+	}
+	for _, test := range tests {
+		got, err := ParseTokenAmount(test.input, test.decimals)
+		if test.want == Empty {
+			if err == nil {
+				t.Errorf("ParseTokenAmount(%q, %d) = %s, want an error", test.input, test.decimals, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTokenAmount(%q, %d) failed: %v", test.input, test.decimals, err)
+			continue
+		}
+		if got.String() != test.want {
+			t.Errorf("ParseTokenAmount(%q, %d) = %s, want %s", test.input, test.decimals, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,804 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/contracts/erc1155"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+const erc1155Contract string = "erc1155"
+
+// interface ERC1155
+// {
+//     function safeTransferFrom(address _from, address _to, uint256 _id, uint256 _value, bytes calldata _data) external;
+//     function safeBatchTransferFrom(address _from, address _to, uint256[] calldata _ids, uint256[] calldata _values, bytes calldata _data) external;
+//     function balanceOf(address _owner, uint256 _id) external view returns (uint256);
+//     function balanceOfBatch(address[] calldata _owners, uint256[] calldata _ids) external view returns (uint256[] memory);
+//     function setApprovalForAll(address _operator, bool _approved) external;
+//     function isApprovedForAll(address _owner, address _operator) external view returns (bool);
+//     function uri(uint256 _id) external view returns (string memory);
+//
+//     event TransferSingle(address indexed _operator, address indexed _from, address indexed _to, uint256 _id, uint256 _value);
+//     event TransferBatch(address indexed _operator, address indexed _from, address indexed _to, uint256[] _ids, uint256[] _values);
+//     event ApprovalForAll(address indexed _owner, address indexed _operator, bool _approved);
+//     event URI(string _value, uint256 indexed _id);
+// }
+
+func erc1155Paths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      ContractPath(erc1155Contract, "balanceOf"),
+			HelpSynopsis: "Returns the balance of a single token type held by an address.",
+			HelpDescription: `
+
+Returns the balance of a single ERC-1155 token type held by an address.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-1155 contract.",
+				},
+				"owner": {
+					Type:        framework.TypeString,
+					Description: "The address that holds the tokens - defaults to this account.",
+				},
+				"id": {
+					Type:        framework.TypeString,
+					Description: "The token type.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC1155BalanceOf,
+				logical.CreateOperation: b.pathERC1155BalanceOf,
+				logical.UpdateOperation: b.pathERC1155BalanceOf,
+			},
+		},
+		{
+			Pattern:      ContractPath(erc1155Contract, "balanceOfBatch"),
+			HelpSynopsis: "Returns the balances of several token types.",
+			HelpDescription: `
+
+Returns the balances of several ERC-1155 token types. The owners and ids are paired
+up by position. If no owners are provided, this account is used for every id; if a
+single owner is provided, it is used for every id.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-1155 contract.",
+				},
+				"owners": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The addresses that hold the tokens - defaults to this account.",
+				},
+				"ids": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The token types.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC1155BalanceOfBatch,
+				logical.CreateOperation: b.pathERC1155BalanceOfBatch,
+				logical.UpdateOperation: b.pathERC1155BalanceOfBatch,
+			},
+		},
+		{
+			Pattern:      ContractPath(erc1155Contract, "safeTransferFrom"),
+			HelpSynopsis: "Transfers an amount of a single token type to another address.",
+			HelpDescription: `
+
+Transfers an amount of a single ERC-1155 token type from this account to another address.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-1155 contract.",
+				},
+				"to": {
+					Type:        framework.TypeString,
+					Description: "The address or ENS name to transfer the tokens to.",
+				},
+				"id": {
+					Type:        framework.TypeString,
+					Description: "The token type to transfer.",
+				},
+				"amount": {
+					Type:        framework.TypeString,
+					Default:     "1",
+					Description: "The number of tokens to transfer.",
+				},
+				"data": {
+					Type:        framework.TypeString,
+					Description: "Additional data with no specified format, sent in call to \"to\".",
+				},
+				"encoding": {
+					Type:        framework.TypeString,
+					Default:     "utf8",
+					Description: "The encoding of the data.",
+				},
+				"gas_limit": gasLimitField(),
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathERC1155SafeTransferFrom,
+				logical.UpdateOperation: b.pathERC1155SafeTransferFrom,
+			},
+		},
+		{
+			Pattern:      ContractPath(erc1155Contract, "safeBatchTransferFrom"),
+			HelpSynopsis: "Transfers amounts of several token types to another address.",
+			HelpDescription: `
+
+Transfers amounts of several ERC-1155 token types from this account to another address.
+The ids and amounts are paired up by position.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-1155 contract.",
+				},
+				"to": {
+					Type:        framework.TypeString,
+					Description: "The address or ENS name to transfer the tokens to.",
+				},
+				"ids": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The token types to transfer.",
+				},
+				"amounts": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The number of tokens of each type to transfer.",
+				},
+				"data": {
+					Type:        framework.TypeString,
+					Description: "Additional data with no specified format, sent in call to \"to\".",
+				},
+				"encoding": {
+					Type:        framework.TypeString,
+					Default:     "utf8",
+					Description: "The encoding of the data.",
+				},
+				"gas_limit": gasLimitField(),
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathERC1155SafeBatchTransferFrom,
+				logical.UpdateOperation: b.pathERC1155SafeBatchTransferFrom,
+			},
+		},
+		{
+			Pattern:      ContractPath(erc1155Contract, "setApprovalForAll"),
+			HelpSynopsis: "Enables or disables approval for a third party to manage all tokens.",
+			HelpDescription: `
+
+Enables or disables approval for a third party ("operator") to manage all of this
+account's tokens in the contract.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-1155 contract.",
+				},
+				"operator": {
+					Type:        framework.TypeString,
+					Description: "Address or ENS name to add to the set of authorized operators.",
+				},
+				"approved": {
+					Type:        framework.TypeBool,
+					Description: "True if the operator is approved, false to revoke approval.",
+					Default:     false,
+				},
+				"gas_limit": gasLimitField(),
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathERC1155SetApprovalForAll,
+				logical.UpdateOperation: b.pathERC1155SetApprovalForAll,
+			},
+		},
+		{
+			Pattern:      ContractPath(erc1155Contract, "isApprovedForAll"),
+			HelpSynopsis: "Returns true if operator is an approved operator for owner, false otherwise.",
+			HelpDescription: `
+
+Returns true if operator is an approved operator for owner, false otherwise.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-1155 contract.",
+				},
+				"owner": {
+					Type:        framework.TypeString,
+					Description: "The address that owns the tokens - defaults to this account.",
+				},
+				"operator": {
+					Type:        framework.TypeString,
+					Description: "The address or ENS name that acts on behalf of the owner.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC1155IsApprovedForAll,
+				logical.CreateOperation: b.pathERC1155IsApprovedForAll,
+				logical.UpdateOperation: b.pathERC1155IsApprovedForAll,
+			},
+		},
+		{
+			Pattern:      ContractPath(erc1155Contract, "uri"),
+			HelpSynopsis: "Returns the metadata URI for a token type.",
+			HelpDescription: `
+
+Returns the metadata URI for a token type. If the URI contains the "{id}"
+placeholder, token_uri has it replaced by the hex token id as EIP-1155 specifies.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-1155 contract.",
+				},
+				"id": {
+					Type:        framework.TypeString,
+					Description: "The token type.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC1155URI,
+				logical.CreateOperation: b.pathERC1155URI,
+				logical.UpdateOperation: b.pathERC1155URI,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathERC1155BalanceOf(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	id := util.ValidNumber(data.Get("id").(string))
+	if id == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	tokenSession, tokenAddress, err := b.erc1155CallerSession(ctx, req, client, config, data)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := b.ownerOrSelf(ctx, req, config, data)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := tokenSession.BalanceOf(*owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"contract": tokenAddress.Hex(),
+			"owner":    owner.Hex(),
+			"id":       id.String(),
+			"balance":  balance.String(),
+		},
+	}, nil
+}
+
+func (b *PluginBackend) pathERC1155BalanceOfBatch(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := erc1155Numbers(data.Get("ids").([]string), "token ID")
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("at least one token ID is required")
+	}
+
+	var owners []common.Address
+	for _, owner := range data.Get("owners").([]string) {
+		address, err := config.parseAddress(owner)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owners: %v", err)
+		}
+		owners = append(owners, *address)
+	}
+	switch len(owners) {
+	case 0, 1:
+		var owner common.Address
+		if len(owners) == 1 {
+			owner = owners[0]
+		} else {
+			address, err := b.ownerOrSelf(ctx, req, config, data)
+			if err != nil {
+				return nil, err
+			}
+			owner = *address
+		}
+		owners = make([]common.Address, len(ids))
+		for i := range owners {
+			owners[i] = owner
+		}
+	case len(ids):
+	default:
+		return nil, fmt.Errorf("%d owners can't be paired with %d token IDs", len(owners), len(ids))
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	tokenSession, tokenAddress, err := b.erc1155CallerSession(ctx, req, client, config, data)
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := tokenSession.BalanceOfBatch(owners, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]interface{}
+	for i, balance := range balances {
+		results = append(results, map[string]interface{}{
+			"owner":   owners[i].Hex(),
+			"id":      ids[i].String(),
+			"balance": balance.String(),
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"contract": tokenAddress.Hex(),
+			"balances": results,
+		},
+	}, nil
+}
+
+func (b *PluginBackend) pathERC1155SafeTransferFrom(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	id := util.ValidNumber(data.Get("id").(string))
+	if id == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
+	amount := util.ValidNumber(data.Get("amount").(string))
+	if amount == nil {
+		return nil, fmt.Errorf("invalid amount")
+	}
+	additionalData, err := erc1155Data(data)
+	if err != nil {
+		return nil, err
+	}
+
+	accountJSON, err := b.accountFor(ctx, req, config, name)
+	if err != nil {
+		return nil, err
+	}
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	toAddress, toName, err := b.resolveField(ctx, client, config, data, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.ValidAddress(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	err = accountJSON.ValidAddress(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenSession, account, tokenAddress, err := b.erc1155Session(ctx, client, config, accountJSON, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := b.withGasLimit(ctx, client, config, accountJSON, data, &tokenSession.TransactOpts, &contractCall{
+		contract: *tokenAddress,
+		abi:      erc1155.Erc1155ABI,
+		method:   "safeTransferFrom",
+		args:     []interface{}{account.Address, *toAddress, id, amount, additionalData},
+	}); err != nil {
+		return nil, err
+	}
+	tx, err := tokenSession.SafeTransferFrom(account.Address, *toAddress, id, amount, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc1155Contract+"/safeTransferFrom"); err != nil {
+		return nil, err
+	}
+
+	var signedTxBuff bytes.Buffer
+	tx.EncodeRLP(&signedTxBuff)
+	return withENSName(&logical.Response{
+		Data: map[string]interface{}{
+			"contract":           tokenAddress.Hex(),
+			"id":                 id.String(),
+			"amount":             amount.String(),
+			"transaction_hash":   tx.Hash().Hex(),
+			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
+			"from":               account.Address.Hex(),
+			"to":                 toAddress.String(),
+			"nonce":              tx.Nonce(),
+			"gas_price":          tx.GasPrice(),
+			"gas_limit":          tx.Gas(),
+		},
+	}, "to", toName), nil
+}
+
+func (b *PluginBackend) pathERC1155SafeBatchTransferFrom(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	ids, err := erc1155Numbers(data.Get("ids").([]string), "token ID")
+	if err != nil {
+		return nil, err
+	}
+	amounts, err := erc1155Numbers(data.Get("amounts").([]string), "amount")
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 || len(ids) != len(amounts) {
+		return nil, fmt.Errorf("%d token IDs can't be paired with %d amounts", len(ids), len(amounts))
+	}
+	additionalData, err := erc1155Data(data)
+	if err != nil {
+		return nil, err
+	}
+
+	accountJSON, err := b.accountFor(ctx, req, config, name)
+	if err != nil {
+		return nil, err
+	}
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	toAddress, toName, err := b.resolveField(ctx, client, config, data, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.ValidAddress(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	err = accountJSON.ValidAddress(toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenSession, account, tokenAddress, err := b.erc1155Session(ctx, client, config, accountJSON, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := b.withGasLimit(ctx, client, config, accountJSON, data, &tokenSession.TransactOpts, &contractCall{
+		contract: *tokenAddress,
+		abi:      erc1155.Erc1155ABI,
+		method:   "safeBatchTransferFrom",
+		args:     []interface{}{account.Address, *toAddress, ids, amounts, additionalData},
+	}); err != nil {
+		return nil, err
+	}
+	tx, err := tokenSession.SafeBatchTransferFrom(account.Address, *toAddress, ids, amounts, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc1155Contract+"/safeBatchTransferFrom"); err != nil {
+		return nil, err
+	}
+
+	var signedTxBuff bytes.Buffer
+	tx.EncodeRLP(&signedTxBuff)
+	return withENSName(&logical.Response{
+		Data: map[string]interface{}{
+			"contract":           tokenAddress.Hex(),
+			"ids":                data.Get("ids").([]string),
+			"amounts":            data.Get("amounts").([]string),
+			"transaction_hash":   tx.Hash().Hex(),
+			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
+			"from":               account.Address.Hex(),
+			"to":                 toAddress.String(),
+			"nonce":              tx.Nonce(),
+			"gas_price":          tx.GasPrice(),
+			"gas_limit":          tx.Gas(),
+		},
+	}, "to", toName), nil
+}
+
+func (b *PluginBackend) pathERC1155SetApprovalForAll(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	approved := data.Get("approved").(bool)
+
+	accountJSON, err := b.accountFor(ctx, req, config, name)
+	if err != nil {
+		return nil, err
+	}
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	operator, operatorName, err := b.resolveField(ctx, client, config, data, "operator")
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoking an operator can't hand over any tokens, so policy only applies to granting approval
+	if approved {
+		err = config.ValidAddress(operator)
+		if err != nil {
+			return nil, err
+		}
+		err = accountJSON.ValidAddress(operator)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tokenSession, account, tokenAddress, err := b.erc1155Session(ctx, client, config, accountJSON, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := b.withGasLimit(ctx, client, config, accountJSON, data, &tokenSession.TransactOpts, &contractCall{
+		contract: *tokenAddress,
+		abi:      erc1155.Erc1155ABI,
+		method:   "setApprovalForAll",
+		args:     []interface{}{*operator, approved},
+	}); err != nil {
+		return nil, err
+	}
+	tx, err := tokenSession.SetApprovalForAll(*operator, approved)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc1155Contract+"/setApprovalForAll"); err != nil {
+		return nil, err
+	}
+
+	var signedTxBuff bytes.Buffer
+	tx.EncodeRLP(&signedTxBuff)
+	return withENSName(&logical.Response{
+		Data: map[string]interface{}{
+			"contract":           tokenAddress.Hex(),
+			"approved":           approved,
+			"transaction_hash":   tx.Hash().Hex(),
+			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
+			"from":               account.Address.Hex(),
+			"operator":           operator.String(),
+			"nonce":              tx.Nonce(),
+			"gas_price":          tx.GasPrice(),
+			"gas_limit":          tx.Gas(),
+		},
+	}, "operator", operatorName), nil
+}
+
+func (b *PluginBackend) pathERC1155IsApprovedForAll(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	tokenSession, tokenAddress, err := b.erc1155CallerSession(ctx, req, client, config, data)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := b.ownerOrSelf(ctx, req, config, data)
+	if err != nil {
+		return nil, err
+	}
+	operator, operatorName, err := b.resolveField(ctx, client, config, data, "operator")
+	if err != nil {
+		return nil, err
+	}
+
+	approved, err := tokenSession.IsApprovedForAll(*owner, *operator)
+	if err != nil {
+		return nil, err
+	}
+
+	return withENSName(&logical.Response{
+		Data: map[string]interface{}{
+			"contract": tokenAddress.Hex(),
+			"approved": approved,
+			"operator": operator.Hex(),
+			"owner":    owner.Hex(),
+		},
+	}, "operator", operatorName), nil
+}
+
+func (b *PluginBackend) pathERC1155URI(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	id := util.ValidNumber(data.Get("id").(string))
+	if id == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	tokenSession, tokenAddress, err := b.erc1155CallerSession(ctx, req, client, config, data)
+	if err != nil {
+		return nil, err
+	}
+
+	uri, err := tokenSession.Uri(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"contract":  tokenAddress.Hex(),
+			"id":        id.String(),
+			"uri":       uri,
+			"token_uri": strings.Replace(uri, "{id}", fmt.Sprintf("%064x", id), -1),
+		},
+	}, nil
+}
+
+// erc1155CallerSession binds a read-only session to the multi-token contract
+func (b *PluginBackend) erc1155CallerSession(ctx context.Context, req *logical.Request, client *ethclient.Client, config *ConfigJSON, data *framework.FieldData) (*erc1155.Erc1155CallerSession, *common.Address, error) {
+	// Reads are subject to the account's restrictions like everything else on its path
+	if _, err := b.accountFor(ctx, req, config, data.Get("name").(string)); err != nil {
+		return nil, nil, err
+	}
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	instance, err := erc1155.NewErc1155Caller(tokenAddress, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &erc1155.Erc1155CallerSession{
+		Contract: instance,
+		CallOpts: bind.CallOpts{},
+	}, &tokenAddress, nil
+}
+
+// erc1155Session binds a session that signs with the account's key and prices its
+// transactions according to the fee policy
+func (b *PluginBackend) erc1155Session(ctx context.Context, client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, data *framework.FieldData) (*erc1155.Erc1155Session, *accounts.Account, *common.Address, error) {
+	wallet, account, err := getWalletAndAccount(*accountJSON)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	chainID := util.ValidNumber(config.ChainID)
+	if chainID == nil {
+		return nil, nil, nil, fmt.Errorf("invalid chain ID")
+	}
+
+	instance, err := erc1155.NewErc1155(tokenAddress, client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// The gas limit depends on the call, so the path sets it with withGasLimit
+	transactOpts.GasPrice, err = b.gasPrice(ctx, config, accountJSON, data)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &erc1155.Erc1155Session{
+		Contract:     instance,
+		CallOpts:     bind.CallOpts{},
+		TransactOpts: *transactOpts,
+	}, account, &tokenAddress, nil
+}
+
+// erc1155Numbers parses a list of token IDs or amounts
+func erc1155Numbers(values []string, kind string) ([]*big.Int, error) {
+	var numbers []*big.Int
+	for _, value := range values {
+		number := util.ValidNumber(value)
+		if number == nil {
+			return nil, fmt.Errorf("invalid %s %s", kind, value)
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+// erc1155Data decodes the additional data passed along to the recipient
+func erc1155Data(data *framework.FieldData) ([]byte, error) {
+	dataOrFile := data.Get("data").(string)
+	encoding := data.Get("encoding").(string)
+	if encoding == HexEncoding {
+		return util.Decode([]byte(dataOrFile))
+	} else if encoding == Utf8Encoding {
+		return []byte(dataOrFile), nil
+	}
+	return nil, fmt.Errorf("invalid encoding encountered - %s", encoding)
+}
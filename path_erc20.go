@@ -13,9 +13,7 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/immutability-io/vault-ethereum/contracts/erc20"
@@ -51,7 +49,8 @@ Return the balance for an address's ERC-20 holdings.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-20 token.",
@@ -71,7 +70,8 @@ Return the total supply for a ERC-20 token.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-20 token.",
@@ -92,20 +92,22 @@ Transfer some ERC-20 holdings to another address.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-20 token.",
 				},
 				"to": {
 					Type:        framework.TypeString,
-					Description: "The address of the wallet to send tokens to.",
+					Description: "The address or ENS name of the wallet to send tokens to.",
 				},
 				"tokens": {
 					Type:        framework.TypeString,
 					Default:     "0",
-					Description: "The number of tokens to transfer.",
+					Description: "The number of tokens to transfer, such as 1.5, or 0x hex in the token's smallest unit.",
 				},
+				"gas_limit": gasLimitField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -122,7 +124,8 @@ Transfer some ERC-20 holdings from another address to this address.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-20 token.",
@@ -134,8 +137,9 @@ Transfer some ERC-20 holdings from another address to this address.
 				"tokens": {
 					Type:        framework.TypeString,
 					Default:     "0",
-					Description: "The number of tokens to transfer.",
+					Description: "The number of tokens to transfer, such as 1.5, or 0x hex in the token's smallest unit.",
 				},
+				"gas_limit": gasLimitField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -153,20 +157,22 @@ If this function is called again it overwrites the current allowance with _value
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-20 token.",
 				},
 				"spender": {
 					Type:        framework.TypeString,
-					Description: "The address of the spender.",
+					Description: "The address or ENS name of the spender.",
 				},
 				"tokens": {
 					Type:        framework.TypeString,
 					Default:     "0",
-					Description: "The number of tokens to transfer.",
+					Description: "The number of tokens to transfer, such as 1.5, or 0x hex in the token's smallest unit.",
 				},
+				"gas_limit": gasLimitField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -178,13 +184,13 @@ If this function is called again it overwrites the current allowance with _value
 }
 
 func (b *PluginBackend) pathERC20BalanceOf(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -193,12 +199,15 @@ func (b *PluginBackend) pathERC20BalanceOf(ctx context.Context, req *logical.Req
 		return nil, err
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
 
-	contractAddress := common.HexToAddress(data.Get("contract").(string))
+	contractAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 	instance, err := erc20.NewErc20(contractAddress, client)
 	if err != nil {
 		return nil, err
@@ -245,14 +254,13 @@ func (b *PluginBackend) pathERC20BalanceOf(ctx context.Context, req *logical.Req
 }
 
 func (b *PluginBackend) pathERC20Transfer(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	var tokens *big.Int
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -261,14 +269,17 @@ func (b *PluginBackend) pathERC20Transfer(ctx context.Context, req *logical.Requ
 		return nil, err
 	}
 
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 
 	chainID := util.ValidNumber(config.ChainID)
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -299,18 +310,13 @@ func (b *PluginBackend) pathERC20Transfer(ctx context.Context, req *logical.Requ
 		return nil, err
 	}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "to")
+	transactionParams, err := b.getBaseData(client, config, accountJSON, account.Address, data, "to")
 	if err != nil {
 		return nil, err
 	}
-	_, ok := data.GetOk("tokens")
-	if ok {
-		tokens = util.ValidNumber(data.Get("tokens").(string))
-		if tokens == nil {
-			return nil, fmt.Errorf("number of tokens are required")
-		}
-	} else {
-		tokens = util.ValidNumber("0")
+	tokenAmount, err := ParseTokenAmount(data.Get("tokens").(string), decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tokens: %v", err)
 	}
 
 	err = config.ValidAddress(transactionParams.Address)
@@ -321,11 +327,20 @@ func (b *PluginBackend) pathERC20Transfer(ctx context.Context, req *logical.Requ
 	if err != nil {
 		return nil, err
 	}
-	tokenAmount := util.TokenAmount(tokens.Int64(), decimals)
 	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
 	if err != nil {
 		return nil, err
 	}
+	transactOpts.GasPrice = transactionParams.GasPrice
+	transactOpts, err = b.withGasLimit(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: tokenAddress,
+		abi:      erc20.Erc20ABI,
+		method:   "transfer",
+		args:     []interface{}{*transactionParams.Address, tokenAmount},
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	//transactOpts needs gas etc.
 	tokenSession := &erc20.Erc20Session{
@@ -338,10 +353,13 @@ func (b *PluginBackend) pathERC20Transfer(ctx context.Context, req *logical.Requ
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc20Contract+"/transfer"); err != nil {
+		return nil, err
+	}
 
 	var signedTxBuff bytes.Buffer
 	tx.EncodeRLP(&signedTxBuff)
-	return &logical.Response{
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"contract":           tokenAddress.Hex(),
 			"symbol":             symbol,
@@ -355,22 +373,25 @@ func (b *PluginBackend) pathERC20Transfer(ctx context.Context, req *logical.Requ
 			"gas_price":          tx.GasPrice(),
 			"gas_limit":          tx.Gas(),
 		},
-	}, nil
+	}, "to", transactionParams.AddressName), nil
 
 }
 
 func (b *PluginBackend) pathERC20TotalSupply(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
 
-	contractAddress := common.HexToAddress(data.Get("contract").(string))
+	contractAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 	instance, err := erc20.NewErc20(contractAddress, client)
 	if err != nil {
 		return nil, err
@@ -417,14 +438,13 @@ func (b *PluginBackend) pathERC20TotalSupply(ctx context.Context, req *logical.R
 }
 
 func (b *PluginBackend) pathERC20Approve(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	var tokens *big.Int
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -432,14 +452,17 @@ func (b *PluginBackend) pathERC20Approve(ctx context.Context, req *logical.Reque
 	if err != nil {
 		return nil, err
 	}
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 
 	chainID := util.ValidNumber(config.ChainID)
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -470,7 +493,7 @@ func (b *PluginBackend) pathERC20Approve(ctx context.Context, req *logical.Reque
 		return nil, err
 	}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "spender")
+	transactionParams, err := b.getBaseData(client, config, accountJSON, account.Address, data, "spender")
 	if err != nil {
 		return nil, err
 	}
@@ -483,20 +506,24 @@ func (b *PluginBackend) pathERC20Approve(ctx context.Context, req *logical.Reque
 	if err != nil {
 		return nil, err
 	}
-	_, ok := data.GetOk("tokens")
-	if ok {
-		tokens = util.ValidNumber(data.Get("tokens").(string))
-		if tokens == nil {
-			return nil, fmt.Errorf("number of tokens are required")
-		}
-	} else {
-		tokens = util.ValidNumber("0")
+	tokenAmount, err := ParseTokenAmount(data.Get("tokens").(string), decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tokens: %v", err)
 	}
-	tokenAmount := util.TokenAmount(tokens.Int64(), decimals)
 	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
 	if err != nil {
 		return nil, err
 	}
+	transactOpts.GasPrice = transactionParams.GasPrice
+	transactOpts, err = b.withGasLimit(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: tokenAddress,
+		abi:      erc20.Erc20ABI,
+		method:   "approve",
+		args:     []interface{}{*transactionParams.Address, tokenAmount},
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	//transactOpts needs gas etc.
 	tokenSession := &erc20.Erc20Session{
@@ -509,10 +536,13 @@ func (b *PluginBackend) pathERC20Approve(ctx context.Context, req *logical.Reque
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc20Contract+"/approve"); err != nil {
+		return nil, err
+	}
 
 	var signedTxBuff bytes.Buffer
 	tx.EncodeRLP(&signedTxBuff)
-	return &logical.Response{
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"contract":           tokenAddress.Hex(),
 			"symbol":             symbol,
@@ -526,18 +556,17 @@ func (b *PluginBackend) pathERC20Approve(ctx context.Context, req *logical.Reque
 			"gas_price":          tx.GasPrice(),
 			"gas_limit":          tx.Gas(),
 		},
-	}, nil
+	}, "spender", transactionParams.AddressName), nil
 
 }
 func (b *PluginBackend) pathERC20TransferFrom(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	var tokens *big.Int
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -546,14 +575,17 @@ func (b *PluginBackend) pathERC20TransferFrom(ctx context.Context, req *logical.
 		return nil, err
 	}
 
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 
 	chainID := util.ValidNumber(config.ChainID)
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -584,7 +616,7 @@ func (b *PluginBackend) pathERC20TransferFrom(ctx context.Context, req *logical.
 		return nil, err
 	}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "from")
+	transactionParams, err := b.getBaseData(client, config, accountJSON, account.Address, data, "from")
 	if err != nil {
 		return nil, err
 	}
@@ -597,20 +629,24 @@ func (b *PluginBackend) pathERC20TransferFrom(ctx context.Context, req *logical.
 	if err != nil {
 		return nil, err
 	}
-	_, ok := data.GetOk("tokens")
-	if ok {
-		tokens = util.ValidNumber(data.Get("tokens").(string))
-		if tokens == nil {
-			return nil, fmt.Errorf("number of tokens are required")
-		}
-	} else {
-		tokens = util.ValidNumber("0")
+	tokenAmount, err := ParseTokenAmount(data.Get("tokens").(string), decimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tokens: %v", err)
 	}
-	tokenAmount := util.TokenAmount(tokens.Int64(), decimals)
 	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
 	if err != nil {
 		return nil, err
 	}
+	transactOpts.GasPrice = transactionParams.GasPrice
+	transactOpts, err = b.withGasLimit(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: tokenAddress,
+		abi:      erc20.Erc20ABI,
+		method:   "transferFrom",
+		args:     []interface{}{*transactionParams.Address, account.Address, tokenAmount},
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	//transactOpts needs gas etc.
 	tokenSession := &erc20.Erc20Session{
@@ -623,10 +659,13 @@ func (b *PluginBackend) pathERC20TransferFrom(ctx context.Context, req *logical.
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc20Contract+"/transferFrom"); err != nil {
+		return nil, err
+	}
 
 	var signedTxBuff bytes.Buffer
 	tx.EncodeRLP(&signedTxBuff)
-	return &logical.Response{
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"contract":           tokenAddress.Hex(),
 			"symbol":             symbol,
@@ -640,6 +679,6 @@ func (b *PluginBackend) pathERC20TransferFrom(ctx context.Context, req *logical.
 			"gas_price":          tx.GasPrice(),
 			"gas_limit":          tx.Gas(),
 		},
-	}, nil
+	}, "from", transactionParams.AddressName), nil
 
 }
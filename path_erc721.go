@@ -137,14 +137,15 @@ Transfers the ownership of an NFT from one address to another address.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
 				},
 				"to": {
 					Type:        framework.TypeString,
-					Description: "The address to transfer the NFT to.",
+					Description: "The address or ENS name to transfer the NFT to.",
 				},
 				"token_id": {
 					Type:        framework.TypeString,
@@ -159,6 +160,7 @@ Transfers the ownership of an NFT from one address to another address.
 					Default:     "utf8",
 					Description: "The encoding of the data.",
 				},
+				"gas_limit": gasLimitField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -166,6 +168,39 @@ Transfers the ownership of an NFT from one address to another address.
 				logical.UpdateOperation: b.pathERC721SafeTransferFrom,
 			},
 		},
+		{
+			Pattern:      ContractPath(erc721Contract, "transferFrom"),
+			HelpSynopsis: "Transfers the ownership of an NFT without checking the recipient can receive it.",
+			HelpDescription: `
+
+Transfers the ownership of an NFT from this account to another address. Unlike
+safeTransferFrom, the recipient is not asked to acknowledge the transfer, so an NFT
+sent to a contract that can't handle it may be lost.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-721 NFT.",
+				},
+				"to": {
+					Type:        framework.TypeString,
+					Description: "The address or ENS name to transfer the NFT to.",
+				},
+				"token_id": {
+					Type:        framework.TypeString,
+					Description: "The NFT to transfer.",
+				},
+				"gas_limit": gasLimitField(),
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathERC721TransferFrom,
+				logical.UpdateOperation: b.pathERC721TransferFrom,
+			},
+		},
 		{
 			Pattern:      ContractPath(erc721Contract, "approve"),
 			HelpSynopsis: "Set or reaffirm the approved address for an NFT",
@@ -175,20 +210,22 @@ Set or reaffirm the approved address for an NFT.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
 				},
 				"approved": {
 					Type:        framework.TypeString,
-					Description: "The address to approve as operator of the NFT. Defaults to RLP empty byte sequence..",
+					Description: "The address or ENS name to approve as operator of the NFT. Defaults to RLP empty byte sequence..",
 					Default:     util.ZeroAddress,
 				},
 				"token_id": {
 					Type:        framework.TypeString,
 					Description: "The NFT to approve.",
 				},
+				"gas_limit": gasLimitField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -206,20 +243,22 @@ msg.senders assets.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
 				},
 				"operator": {
 					Type:        framework.TypeString,
-					Description: "Address to add to the set of authorized operators.",
+					Description: "Address or ENS name to add to the set of authorized operators.",
 				},
 				"approved": {
 					Type:        framework.TypeBool,
 					Description: "True if the operators is approved, false to revoke approval.",
 					Default:     false,
 				},
+				"gas_limit": gasLimitField(),
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -237,18 +276,20 @@ considered invalid, and this function throws for queries about the zero address.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
 				},
 				"owner": {
 					Type:        framework.TypeString,
-					Description: "Address to add to the set of authorized operators.",
+					Description: "The address that owns the NFTs - defaults to this account.",
 				},
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721BalanceOf,
 				logical.CreateOperation: b.pathERC721BalanceOf,
 				logical.UpdateOperation: b.pathERC721BalanceOf,
 			},
@@ -263,7 +304,8 @@ invalid, and queries about them do throw.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
@@ -275,6 +317,7 @@ invalid, and queries about them do throw.
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721OwnerOf,
 				logical.CreateOperation: b.pathERC721OwnerOf,
 				logical.UpdateOperation: b.pathERC721OwnerOf,
 			},
@@ -288,7 +331,8 @@ Get the approved address for a single NFT.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
@@ -300,6 +344,7 @@ Get the approved address for a single NFT.
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721GetApproved,
 				logical.CreateOperation: b.pathERC721GetApproved,
 				logical.UpdateOperation: b.pathERC721GetApproved,
 			},
@@ -313,22 +358,24 @@ Returns true if operator is an approved operator for owner, false otherwise.
 
 `,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
 				},
 				"owner": {
 					Type:        framework.TypeString,
-					Description: "The address that owns the NFTs",
+					Description: "The address that owns the NFTs - defaults to this account.",
 				},
 				"operator": {
 					Type:        framework.TypeString,
-					Description: "The address that acts on behalf of the owner.",
+					Description: "The address or ENS name that acts on behalf of the owner.",
 				},
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721IsApprovedForAll,
 				logical.CreateOperation: b.pathERC721IsApprovedForAll,
 				logical.UpdateOperation: b.pathERC721IsApprovedForAll,
 			},
@@ -342,7 +389,8 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 		
 		`,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
@@ -354,6 +402,7 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721TokenByIndex,
 				logical.CreateOperation: b.pathERC721TokenByIndex,
 				logical.UpdateOperation: b.pathERC721TokenByIndex,
 			},
@@ -367,14 +416,15 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 		
 		`,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
 				},
 				"owner": {
 					Type:        framework.TypeString,
-					Description: "Address to add to the set of authorized operators.",
+					Description: "The address that owns the NFTs - defaults to this account.",
 				},
 				"index": {
 					Type:        framework.TypeString,
@@ -383,6 +433,7 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721TokenOfOwnerByIndex,
 				logical.CreateOperation: b.pathERC721TokenOfOwnerByIndex,
 				logical.UpdateOperation: b.pathERC721TokenOfOwnerByIndex,
 			},
@@ -397,7 +448,8 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 		
 		`,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
@@ -405,6 +457,7 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721Metadata,
 				logical.CreateOperation: b.pathERC721Metadata,
 				logical.UpdateOperation: b.pathERC721Metadata,
 			},
@@ -418,7 +471,8 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 		
 		`,
 			Fields: map[string]*framework.FieldSchema{
-				"name": {Type: framework.TypeString},
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
 				"contract": {
 					Type:        framework.TypeString,
 					Description: "The address of the ERC-721 NFT.",
@@ -430,6 +484,7 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 			},
 			ExistenceCheck: pathExistenceCheck,
 			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721TokenURI,
 				logical.CreateOperation: b.pathERC721TokenURI,
 				logical.UpdateOperation: b.pathERC721TokenURI,
 			},
@@ -459,12 +514,15 @@ TokenByIndex is a free data retrieval call binding the contract method 0x4f6ccce
 
 func (b *PluginBackend) pathERC721SafeTransferFrom(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	var additionalData []byte
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
 	tokenID := util.ValidNumber(data.Get("token_id").(string))
+	if tokenID == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
 	dataOrFile := data.Get("data").(string)
 	encoding := data.Get("encoding").(string)
 	if encoding == "hex" {
@@ -478,7 +536,7 @@ func (b *PluginBackend) pathERC721SafeTransferFrom(ctx context.Context, req *log
 		return nil, fmt.Errorf("invalid encoding encountered - %s", encoding)
 	}
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -487,14 +545,17 @@ func (b *PluginBackend) pathERC721SafeTransferFrom(ctx context.Context, req *log
 		return nil, err
 	}
 
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 
 	chainID := util.ValidNumber(config.ChainID)
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -505,7 +566,7 @@ func (b *PluginBackend) pathERC721SafeTransferFrom(ctx context.Context, req *log
 	}
 	callOpts := &bind.CallOpts{}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "to")
+	transactionParams, err := b.getBaseData(client, config, accountJSON, account.Address, data, "to")
 	if err != nil {
 		return nil, err
 	}
@@ -522,6 +583,16 @@ func (b *PluginBackend) pathERC721SafeTransferFrom(ctx context.Context, req *log
 	if err != nil {
 		return nil, err
 	}
+	transactOpts.GasPrice = transactionParams.GasPrice
+	transactOpts, err = b.withGasLimit(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: tokenAddress,
+		abi:      erc721.Erc721ABI,
+		method:   "safeTransferFrom0",
+		args:     []interface{}{account.Address, *transactionParams.Address, tokenID, additionalData},
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	//transactOpts needs gas etc.
 	tokenSession := &erc721.Erc721Session{
@@ -534,10 +605,13 @@ func (b *PluginBackend) pathERC721SafeTransferFrom(ctx context.Context, req *log
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc721Contract+"/safeTransferFrom"); err != nil {
+		return nil, err
+	}
 
 	var signedTxBuff bytes.Buffer
 	tx.EncodeRLP(&signedTxBuff)
-	return &logical.Response{
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"contract":           tokenAddress.Hex(),
 			"token_id":           tokenID.String(),
@@ -551,30 +625,35 @@ func (b *PluginBackend) pathERC721SafeTransferFrom(ctx context.Context, req *log
 			"gas_price":          tx.GasPrice(),
 			"gas_limit":          tx.Gas(),
 		},
-	}, nil
+	}, "to", transactionParams.AddressName), nil
 }
 
 //   /**
-//    * @dev Set or reaffirm the approved address for an NFT.
-//    * @notice The zero address indicates there is no approved address. Throws unless `msg.sender` is
-//    * the current NFT owner, or an authorized operator of the current owner.
-//    * @param _approved The new approved NFT controller.
-//    * @param _tokenId The NFT to approve.
+//    * @dev Transfers the ownership of an NFT from one address to another address.
+//    * @notice The caller is responsible to confirm that `_to` is capable of receiving NFTs or else
+//    * they may be permanently lost.
+//    * @param _from The current owner of the NFT.
+//    * @param _to The new owner.
+//    * @param _tokenId The NFT to transfer.
 //    */
-//   function approve(
-//     address _approved,
+//   function transferFrom(
+//     address _from,
+//     address _to,
 //     uint256 _tokenId
 //   )
 
-func (b *PluginBackend) pathERC721Approve(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+func (b *PluginBackend) pathERC721TransferFrom(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
 	tokenID := util.ValidNumber(data.Get("token_id").(string))
+	if tokenID == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -583,14 +662,17 @@ func (b *PluginBackend) pathERC721Approve(ctx context.Context, req *logical.Requ
 		return nil, err
 	}
 
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 
 	chainID := util.ValidNumber(config.ChainID)
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -601,16 +683,16 @@ func (b *PluginBackend) pathERC721Approve(ctx context.Context, req *logical.Requ
 	}
 	callOpts := &bind.CallOpts{}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "approved")
+	toAddress, toName, err := b.resolveField(ctx, client, config, data, "to")
 	if err != nil {
 		return nil, err
 	}
 
-	err = config.ValidAddress(transactionParams.Address)
+	err = config.ValidAddress(toAddress)
 	if err != nil {
 		return nil, err
 	}
-	err = accountJSON.ValidAddress(transactionParams.Address)
+	err = accountJSON.ValidAddress(toAddress)
 	if err != nil {
 		return nil, err
 	}
@@ -618,6 +700,15 @@ func (b *PluginBackend) pathERC721Approve(ctx context.Context, req *logical.Requ
 	if err != nil {
 		return nil, err
 	}
+	transactOpts, err = b.withFeePolicy(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: tokenAddress,
+		abi:      erc721.Erc721ABI,
+		method:   "transferFrom",
+		args:     []interface{}{account.Address, *toAddress, tokenID},
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	//transactOpts needs gas etc.
 	tokenSession := &erc721.Erc721Session{
@@ -626,48 +717,55 @@ func (b *PluginBackend) pathERC721Approve(ctx context.Context, req *logical.Requ
 		TransactOpts: *transactOpts,
 	}
 
-	tx, err := tokenSession.Approve(*transactionParams.Address, tokenID)
+	tx, err := tokenSession.TransferFrom(account.Address, *toAddress, tokenID)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc721Contract+"/transferFrom"); err != nil {
+		return nil, err
+	}
 
 	var signedTxBuff bytes.Buffer
 	tx.EncodeRLP(&signedTxBuff)
-	return &logical.Response{
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"contract":           tokenAddress.Hex(),
 			"token_id":           tokenID.String(),
 			"transaction_hash":   tx.Hash().Hex(),
 			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
 			"from":               account.Address.Hex(),
-			"to":                 transactionParams.Address.String(),
+			"to":                 toAddress.String(),
 			"nonce":              tx.Nonce(),
 			"gas_price":          tx.GasPrice(),
 			"gas_limit":          tx.Gas(),
 		},
-	}, nil
+	}, "to", toName), nil
 }
 
 //   /**
-//    * @dev Enables or disables approval for a third party ("operator") to manage all of
-//    * `msg.sender`'s assets. It also emits the ApprovalForAll event.
-//    * @notice The contract MUST allow multiple operators per owner.
-//    * @param _operator Address to add to the set of authorized operators.
-//    * @param _approved True if the operators is approved, false to revoke approval.
+//    * @dev Set or reaffirm the approved address for an NFT.
+//    * @notice The zero address indicates there is no approved address. Throws unless `msg.sender` is
+//    * the current NFT owner, or an authorized operator of the current owner.
+//    * @param _approved The new approved NFT controller.
+//    * @param _tokenId The NFT to approve.
 //    */
-//   function setApprovalForAll(
-//     address _operator,
-//     bool _approved
+//   function approve(
+//     address _approved,
+//     uint256 _tokenId
 //   )
-func (b *PluginBackend) pathERC721SetApprovalForAll(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+
+func (b *PluginBackend) pathERC721Approve(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
-	approved := data.Get("approved").(bool)
+	tokenID := util.ValidNumber(data.Get("token_id").(string))
+	if tokenID == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -676,14 +774,17 @@ func (b *PluginBackend) pathERC721SetApprovalForAll(ctx context.Context, req *lo
 		return nil, err
 	}
 
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 
 	chainID := util.ValidNumber(config.ChainID)
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -694,20 +795,40 @@ func (b *PluginBackend) pathERC721SetApprovalForAll(ctx context.Context, req *lo
 	}
 	callOpts := &bind.CallOpts{}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "operator")
-	if err != nil {
-		return nil, err
+	// The zero address clears the approval, so it is accepted here although address
+	// fields otherwise refuse it
+	var approved *common.Address
+	var approvedName string
+	if cleared, err := util.ParseAddress(data.Get("approved").(string), config.RequireChecksum); err == nil && cleared == (common.Address{}) {
+		approved = &cleared
+	} else {
+		approved, approvedName, err = b.resolveField(ctx, client, config, data, "approved")
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	err = config.ValidAddress(transactionParams.Address)
-	if err != nil {
-		return nil, err
+	// Clearing the approval can't hand the NFT to anyone, so policy only applies to a new controller
+	if approved.Hex() != util.ZeroAddress {
+		err = config.ValidAddress(approved)
+		if err != nil {
+			return nil, err
+		}
+		err = accountJSON.ValidAddress(approved)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = accountJSON.ValidAddress(transactionParams.Address)
+	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
 	if err != nil {
 		return nil, err
 	}
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	transactOpts, err = b.withFeePolicy(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: tokenAddress,
+		abi:      erc721.Erc721ABI,
+		method:   "approve",
+		args:     []interface{}{*approved, tokenID},
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -719,46 +840,51 @@ func (b *PluginBackend) pathERC721SetApprovalForAll(ctx context.Context, req *lo
 		TransactOpts: *transactOpts,
 	}
 
-	tx, err := tokenSession.SetApprovalForAll(*transactionParams.Address, approved)
+	tx, err := tokenSession.Approve(*approved, tokenID)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc721Contract+"/approve"); err != nil {
+		return nil, err
+	}
 
 	var signedTxBuff bytes.Buffer
 	tx.EncodeRLP(&signedTxBuff)
-	return &logical.Response{
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"contract":           tokenAddress.Hex(),
-			"approved":           approved,
+			"token_id":           tokenID.String(),
 			"transaction_hash":   tx.Hash().Hex(),
 			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
 			"from":               account.Address.Hex(),
-			"operator":           transactionParams.Address.String(),
+			"to":                 approved.String(),
 			"nonce":              tx.Nonce(),
 			"gas_price":          tx.GasPrice(),
 			"gas_limit":          tx.Gas(),
 		},
-	}, nil
+	}, "approved", approvedName), nil
 }
 
 //   /**
-//    * @dev Returns the number of NFTs owned by `_owner`. NFTs assigned to the zero address are
-//    * considered invalid, and this function throws for queries about the zero address.
-//    * @param _owner Address for whom to query the balance.
-//    * @return Balance of _owner.
+//    * @dev Enables or disables approval for a third party ("operator") to manage all of
+//    * `msg.sender`'s assets. It also emits the ApprovalForAll event.
+//    * @notice The contract MUST allow multiple operators per owner.
+//    * @param _operator Address to add to the set of authorized operators.
+//    * @param _approved True if the operators is approved, false to revoke approval.
 //    */
-//   function balanceOf(
-//     address _owner
+//   function setApprovalForAll(
+//     address _operator,
+//     bool _approved
 //   )
-
-func (b *PluginBackend) pathERC721BalanceOf(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+func (b *PluginBackend) pathERC721SetApprovalForAll(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
 	name := data.Get("name").(string)
+	approved := data.Get("approved").(bool)
 
-	accountJSON, err := readAccount(ctx, req, name)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
 	if err != nil {
 		return nil, err
 	}
@@ -767,14 +893,17 @@ func (b *PluginBackend) pathERC721BalanceOf(ctx context.Context, req *logical.Re
 		return nil, err
 	}
 
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
 
 	chainID := util.ValidNumber(config.ChainID)
 	if chainID == nil {
 		return nil, fmt.Errorf("invalid chain ID")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
@@ -785,20 +914,32 @@ func (b *PluginBackend) pathERC721BalanceOf(ctx context.Context, req *logical.Re
 	}
 	callOpts := &bind.CallOpts{}
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "owner")
+	operator, operatorName, err := b.resolveField(ctx, client, config, data, "operator")
 	if err != nil {
 		return nil, err
 	}
 
-	err = config.ValidAddress(transactionParams.Address)
-	if err != nil {
-		return nil, err
+	// Revoking an operator can't hand over any NFTs, so policy only applies to granting approval
+	if approved {
+		err = config.ValidAddress(operator)
+		if err != nil {
+			return nil, err
+		}
+		err = accountJSON.ValidAddress(operator)
+		if err != nil {
+			return nil, err
+		}
 	}
-	err = accountJSON.ValidAddress(transactionParams.Address)
+	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
 	if err != nil {
 		return nil, err
 	}
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	transactOpts, err = b.withFeePolicy(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: tokenAddress,
+		abi:      erc721.Erc721ABI,
+		method:   "setApprovalForAll",
+		args:     []interface{}{*operator, approved},
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -810,75 +951,101 @@ func (b *PluginBackend) pathERC721BalanceOf(ctx context.Context, req *logical.Re
 		TransactOpts: *transactOpts,
 	}
 
-	balance, err := tokenSession.BalanceOf(*transactionParams.Address)
+	tx, err := tokenSession.SetApprovalForAll(*operator, approved)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, erc721Contract+"/setApprovalForAll"); err != nil {
+		return nil, err
+	}
 
-	return &logical.Response{
+	var signedTxBuff bytes.Buffer
+	tx.EncodeRLP(&signedTxBuff)
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
-			"contract": tokenAddress.Hex(),
-			"balance":  balance.String(),
-			"owner":    transactionParams.Address.String(),
+			"contract":           tokenAddress.Hex(),
+			"approved":           approved,
+			"transaction_hash":   tx.Hash().Hex(),
+			"signed_transaction": hexutil.Encode(signedTxBuff.Bytes()),
+			"from":               account.Address.Hex(),
+			"operator":           operator.String(),
+			"nonce":              tx.Nonce(),
+			"gas_price":          tx.GasPrice(),
+			"gas_limit":          tx.Gas(),
 		},
-	}, nil
+	}, "operator", operatorName), nil
 }
 
 //   /**
-//    * @dev Returns the address of the owner of the NFT. NFTs assigned to zero address are considered
-//    * invalid, and queries about them do throw.
-//    * @param _tokenId The identifier for an NFT.
-//    * @return Address of _tokenId owner.
+//    * @dev Returns the number of NFTs owned by `_owner`. NFTs assigned to the zero address are
+//    * considered invalid, and this function throws for queries about the zero address.
+//    * @param _owner Address for whom to query the balance.
+//    * @return Balance of _owner.
 //    */
-//   function ownerOf(
-//     uint256 _tokenId
+//   function balanceOf(
+//     address _owner
 //   )
 
-func (b *PluginBackend) pathERC721OwnerOf(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+func (b *PluginBackend) pathERC721BalanceOf(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	name := data.Get("name").(string)
-	tokenID := util.ValidNumber(data.Get("token_id").(string))
 
-	accountJSON, err := readAccount(ctx, req, name)
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-	wallet, account, err := getWalletAndAccount(*accountJSON)
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
 	if err != nil {
 		return nil, err
 	}
-
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
-
-	chainID := util.ValidNumber(config.ChainID)
-	if chainID == nil {
-		return nil, fmt.Errorf("invalid chain ID")
+	owner, err := b.ownerOrSelf(ctx, req, config, data)
+	if err != nil {
+		return nil, err
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	balance, err := tokenSession.BalanceOf(*owner)
 	if err != nil {
 		return nil, err
 	}
 
-	instance, err := erc721.NewErc721(tokenAddress, client)
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"contract": tokenAddress.Hex(),
+			"balance":  balance.String(),
+			"owner":    owner.Hex(),
+		},
+	}, nil
+}
+
+//   /**
+//    * @dev Returns the address of the owner of the NFT. NFTs assigned to zero address are considered
+//    * invalid, and queries about them do throw.
+//    * @param _tokenId The identifier for an NFT.
+//    * @return Address of _tokenId owner.
+//    */
+//   function ownerOf(
+//     uint256 _tokenId
+//   )
+
+func (b *PluginBackend) pathERC721OwnerOf(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	callOpts := &bind.CallOpts{}
+	tokenID := util.ValidNumber(data.Get("token_id").(string))
+	if tokenID == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
 
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-
-	//transactOpts needs gas etc.
-	tokenSession := &erc721.Erc721Session{
-		Contract:     instance,  // Generic contract caller binding to set the session for
-		CallOpts:     *callOpts, // Call options to use throughout this session
-		TransactOpts: *transactOpts,
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
+	if err != nil {
+		return nil, err
 	}
 
 	owner, err := tokenSession.OwnerOf(tokenID)
@@ -906,52 +1073,24 @@ func (b *PluginBackend) pathERC721OwnerOf(ctx context.Context, req *logical.Requ
 //   )
 
 func (b *PluginBackend) pathERC721GetApproved(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	name := data.Get("name").(string)
 	tokenID := util.ValidNumber(data.Get("token_id").(string))
-
-	accountJSON, err := readAccount(ctx, req, name)
-	if err != nil {
-		return nil, err
-	}
-	wallet, account, err := getWalletAndAccount(*accountJSON)
-	if err != nil {
-		return nil, err
-	}
-
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
-
-	chainID := util.ValidNumber(config.ChainID)
-	if chainID == nil {
-		return nil, fmt.Errorf("invalid chain ID")
-	}
-
-	client, err := ethclient.Dial(config.getRPCURL())
-	if err != nil {
-		return nil, err
+	if tokenID == nil {
+		return nil, fmt.Errorf("invalid token ID")
 	}
 
-	instance, err := erc721.NewErc721(tokenAddress, client)
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-	callOpts := &bind.CallOpts{}
-
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
 	if err != nil {
 		return nil, err
 	}
 
-	//transactOpts needs gas etc.
-	tokenSession := &erc721.Erc721Session{
-		Contract:     instance,  // Generic contract caller binding to set the session for
-		CallOpts:     *callOpts, // Call options to use throughout this session
-		TransactOpts: *transactOpts,
-	}
-
 	approved, err := tokenSession.GetApproved(tokenID)
 	if err != nil {
 		return nil, err
@@ -978,122 +1117,61 @@ func (b *PluginBackend) pathERC721GetApproved(ctx context.Context, req *logical.
 //   )
 
 func (b *PluginBackend) pathERC721IsApprovedForAll(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	name := data.Get("name").(string)
 
-	accountJSON, err := readAccount(ctx, req, name)
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-	wallet, account, err := getWalletAndAccount(*accountJSON)
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
 	if err != nil {
 		return nil, err
 	}
-
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
-
-	chainID := util.ValidNumber(config.ChainID)
-	if chainID == nil {
-		return nil, fmt.Errorf("invalid chain ID")
-	}
-
-	client, err := ethclient.Dial(config.getRPCURL())
+	owner, err := b.ownerOrSelf(ctx, req, config, data)
 	if err != nil {
 		return nil, err
 	}
-
-	instance, err := erc721.NewErc721(tokenAddress, client)
+	operator, operatorName, err := b.resolveField(ctx, client, config, data, "operator")
 	if err != nil {
 		return nil, err
 	}
-	callOpts := &bind.CallOpts{}
 
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
-	if err != nil {
-		return nil, err
-	}
-	transactionParams, err := b.getBaseData(client, account.Address, data, "owner")
-	if err != nil {
-		return nil, err
-	}
-	owner := *transactionParams.Address
-	transactionParams, err = b.getBaseData(client, account.Address, data, "operator")
-	if err != nil {
-		return nil, err
-	}
-	operator := *transactionParams.Address
-
-	//transactOpts needs gas etc.
-	tokenSession := &erc721.Erc721Session{
-		Contract:     instance,  // Generic contract caller binding to set the session for
-		CallOpts:     *callOpts, // Call options to use throughout this session
-		TransactOpts: *transactOpts,
-	}
-
-	approved, err := tokenSession.IsApprovedForAll(owner, operator)
+	approved, err := tokenSession.IsApprovedForAll(*owner, *operator)
 	if err != nil {
 		return nil, err
 	}
 
-	return &logical.Response{
+	return withENSName(&logical.Response{
 		Data: map[string]interface{}{
 			"contract": tokenAddress.Hex(),
 			"approved": approved,
 			"operator": operator.Hex(),
 			"owner":    owner.Hex(),
 		},
-	}, nil
+	}, "operator", operatorName), nil
 }
 
 func (b *PluginBackend) pathERC721TokenByIndex(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	name := data.Get("name").(string)
-
-	accountJSON, err := readAccount(ctx, req, name)
-	if err != nil {
-		return nil, err
-	}
-	wallet, account, err := getWalletAndAccount(*accountJSON)
-	if err != nil {
-		return nil, err
-	}
-
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
-
-	chainID := util.ValidNumber(config.ChainID)
-	if chainID == nil {
-		return nil, fmt.Errorf("invalid chain ID")
+	index := util.ValidNumber(data.Get("index").(string))
+	if index == nil {
+		return nil, fmt.Errorf("invalid index")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-
-	instance, err := erc721.NewErc721(tokenAddress, client)
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
 	if err != nil {
 		return nil, err
 	}
-	callOpts := &bind.CallOpts{}
-
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
-	if err != nil {
-		return nil, err
-	}
-	index := util.ValidNumber(data.Get("index").(string))
-
-	//transactOpts needs gas etc.
-	tokenSession := &erc721.Erc721Session{
-		Contract:     instance,  // Generic contract caller binding to set the session for
-		CallOpts:     *callOpts, // Call options to use throughout this session
-		TransactOpts: *transactOpts,
-	}
 
 	tokenID, err := tokenSession.TokenByIndex(index)
 	if err != nil {
@@ -1110,58 +1188,29 @@ func (b *PluginBackend) pathERC721TokenByIndex(ctx context.Context, req *logical
 }
 
 func (b *PluginBackend) pathERC721TokenOfOwnerByIndex(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	name := data.Get("name").(string)
-
-	accountJSON, err := readAccount(ctx, req, name)
-	if err != nil {
-		return nil, err
-	}
-	wallet, account, err := getWalletAndAccount(*accountJSON)
-	if err != nil {
-		return nil, err
-	}
-
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
-
-	chainID := util.ValidNumber(config.ChainID)
-	if chainID == nil {
-		return nil, fmt.Errorf("invalid chain ID")
+	index := util.ValidNumber(data.Get("index").(string))
+	if index == nil {
+		return nil, fmt.Errorf("invalid index")
 	}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-
-	instance, err := erc721.NewErc721(tokenAddress, client)
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
 	if err != nil {
 		return nil, err
 	}
-	callOpts := &bind.CallOpts{}
-
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	owner, err := b.ownerOrSelf(ctx, req, config, data)
 	if err != nil {
 		return nil, err
 	}
-	index := util.ValidNumber(data.Get("index").(string))
 
-	transactionParams, err := b.getBaseData(client, account.Address, data, "owner")
-	if err != nil {
-		return nil, err
-	}
-
-	//transactOpts needs gas etc.
-	tokenSession := &erc721.Erc721Session{
-		Contract:     instance,  // Generic contract caller binding to set the session for
-		CallOpts:     *callOpts, // Call options to use throughout this session
-		TransactOpts: *transactOpts,
-	}
-
-	tokenID, err := tokenSession.TokenOfOwnerByIndex(*transactionParams.Address, index)
+	tokenID, err := tokenSession.TokenOfOwnerByIndex(*owner, index)
 	if err != nil {
 		return nil, err
 	}
@@ -1169,7 +1218,7 @@ func (b *PluginBackend) pathERC721TokenOfOwnerByIndex(ctx context.Context, req *
 	return &logical.Response{
 		Data: map[string]interface{}{
 			"contract": tokenAddress.Hex(),
-			"owner":    transactionParams.Address.Hex(),
+			"owner":    owner.Hex(),
 			"index":    index.String(),
 			"token":    tokenID.String(),
 		},
@@ -1177,51 +1226,20 @@ func (b *PluginBackend) pathERC721TokenOfOwnerByIndex(ctx context.Context, req *
 }
 
 func (b *PluginBackend) pathERC721Metadata(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	name := data.Get("name").(string)
 
-	accountJSON, err := readAccount(ctx, req, name)
-	if err != nil {
-		return nil, err
-	}
-	wallet, account, err := getWalletAndAccount(*accountJSON)
-	if err != nil {
-		return nil, err
-	}
-
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
-
-	chainID := util.ValidNumber(config.ChainID)
-	if chainID == nil {
-		return nil, fmt.Errorf("invalid chain ID")
-	}
-
-	client, err := ethclient.Dial(config.getRPCURL())
-	if err != nil {
-		return nil, err
-	}
-
-	instance, err := erc721.NewErc721(tokenAddress, client)
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-	callOpts := &bind.CallOpts{}
-
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
 	if err != nil {
 		return nil, err
 	}
 
-	//transactOpts needs gas etc.
-	tokenSession := &erc721.Erc721Session{
-		Contract:     instance,  // Generic contract caller binding to set the session for
-		CallOpts:     *callOpts, // Call options to use throughout this session
-		TransactOpts: *transactOpts,
-	}
-
 	supply, err := tokenSession.TotalSupply()
 	if err != nil {
 		return nil, err
@@ -1245,60 +1263,76 @@ func (b *PluginBackend) pathERC721Metadata(ctx context.Context, req *logical.Req
 	}, nil
 }
 func (b *PluginBackend) pathERC721TokenURI(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	config, err := b.configured(ctx, req)
+	config, err := b.configuredNetwork(ctx, req, data)
 	if err != nil {
 		return nil, err
 	}
-	name := data.Get("name").(string)
 	tokenID := util.ValidNumber(data.Get("token_id").(string))
+	if tokenID == nil {
+		return nil, fmt.Errorf("invalid token ID")
+	}
 
-	accountJSON, err := readAccount(ctx, req, name)
+	client, err := b.client(ctx, config)
 	if err != nil {
 		return nil, err
 	}
-	wallet, account, err := getWalletAndAccount(*accountJSON)
+	tokenSession, tokenAddress, err := b.erc721CallerSession(ctx, req, client, config, data)
 	if err != nil {
 		return nil, err
 	}
 
-	tokenAddress := common.HexToAddress(data.Get("contract").(string))
-
-	chainID := util.ValidNumber(config.ChainID)
-	if chainID == nil {
-		return nil, fmt.Errorf("invalid chain ID")
+	tokenURI, err := tokenSession.TokenURI(tokenID)
+	if err != nil {
+		return nil, err
 	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"contract":  tokenAddress.Hex(),
+			"token_id":  tokenID.String(),
+			"token_uri": tokenURI,
+		},
+	}, nil
+}
 
-	client, err := ethclient.Dial(config.getRPCURL())
+// erc721CallerSession binds a read-only session to the NFT contract. View calls
+// don't need a signing transactor, so the account's key is never derived.
+func (b *PluginBackend) erc721CallerSession(ctx context.Context, req *logical.Request, client *ethclient.Client, config *ConfigJSON, data *framework.FieldData) (*erc721.Erc721CallerSession, *common.Address, error) {
+	// Reads are subject to the account's restrictions like everything else on its path
+	if _, err := b.accountFor(ctx, req, config, data.Get("name").(string)); err != nil {
+		return nil, nil, err
+	}
+	tokenAddress, err := config.addressField(data, "contract")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	instance, err := erc721.NewErc721(tokenAddress, client)
+	instance, err := erc721.NewErc721Caller(tokenAddress, client)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	callOpts := &bind.CallOpts{}
+	return &erc721.Erc721CallerSession{
+		Contract: instance,
+		CallOpts: bind.CallOpts{},
+	}, &tokenAddress, nil
+}
 
-	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+// ownerOrSelf returns the owner field if provided, otherwise the account's own address.
+// Either way the account's restrictions apply.
+func (b *PluginBackend) ownerOrSelf(ctx context.Context, req *logical.Request, config *ConfigJSON, data *framework.FieldData) (*common.Address, error) {
+	accountJSON, err := b.accountFor(ctx, req, config, data.Get("name").(string))
 	if err != nil {
 		return nil, err
 	}
-
-	//transactOpts needs gas etc.
-	tokenSession := &erc721.Erc721Session{
-		Contract:     instance,  // Generic contract caller binding to set the session for
-		CallOpts:     *callOpts, // Call options to use throughout this session
-		TransactOpts: *transactOpts,
+	if _, ok := data.GetOk("owner"); ok {
+		address, err := config.addressField(data, "owner")
+		if err != nil {
+			return nil, err
+		}
+		return &address, nil
 	}
-
-	tokenURI, err := tokenSession.TokenURI(tokenID)
+	_, account, err := getWalletAndAccount(*accountJSON)
 	if err != nil {
 		return nil, err
 	}
-	return &logical.Response{
-		Data: map[string]interface{}{
-			"contract":  tokenAddress.Hex(),
-			"token_uri": tokenURI,
-		},
-	}, nil
+	return &account.Address, nil
 }
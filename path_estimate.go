@@ -0,0 +1,325 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/contracts/erc20"
+	"github.com/immutability-io/vault-ethereum/util"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// EstimateTransfer is a transfer of ETH
+	EstimateTransfer string = "transfer"
+	// EstimateTokenTransfer is a transfer of ERC-20 tokens
+	EstimateTokenTransfer string = "token_transfer"
+	// EstimateCall is a call to a contract
+	EstimateCall string = "call"
+	// EstimateDeploy is the deployment of a contract
+	EstimateDeploy string = "deploy"
+)
+
+func estimatePaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      QualifiedPath("accounts/" + framework.GenericNameRegex("name") + "/estimate"),
+			HelpSynopsis: "Estimate what a transaction from an account will cost.",
+			HelpDescription: `
+
+Estimates the gas of a prospective transfer, token transfer, contract call or deploy,
+and the fee at each speed tier in wei, gwei, ether and a fiat currency. Nothing is
+signed or sent. The response says whether the account's balance covers the value
+plus the fee at the tier the fee policy would pay.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"type": {
+					Type:        framework.TypeString,
+					Description: "The kind of transaction: transfer, token_transfer, call or deploy.",
+					Default:     EstimateTransfer,
+				},
+				"to": {
+					Type:        framework.TypeString,
+					Description: "The address or ENS name that receives the ETH or tokens, or the contract that is called.",
+				},
+				"amount": {
+					Type:        framework.TypeString,
+					Description: "Amount of ETH sent - wei, 0x hex wei or a number and a unit, such as 1.5 ether.",
+				},
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-20 token for a token transfer.",
+				},
+				"tokens": {
+					Type:        framework.TypeString,
+					Description: "The number of tokens for a token transfer.",
+				},
+				"data": {
+					Type:        framework.TypeString,
+					Description: "Hex encoded call data for a call, or the compiled contract for a deploy.",
+				},
+				"gas_limit": {
+					Type:        framework.TypeString,
+					Description: "The gas limit for the transaction - if omitted, it is estimated.",
+				},
+				"gas_price": {
+					Type:        framework.TypeString,
+					Description: "The gas price for the transaction - if omitted, the fee policy's tier is used.",
+					Default:     "0",
+				},
+				"currency": {
+					Type:        framework.TypeString,
+					Description: "The fiat currency to value fees in - defaults to the mount's first currency.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathEstimate,
+				logical.UpdateOperation: b.pathEstimate,
+			},
+		},
+	}
+}
+
+// estimateMessage builds the call that is estimated for each kind of transaction, and
+// returns the contract and token amount of a token transfer
+func (b *PluginBackend) estimateMessage(ctx context.Context, backend bind.ContractCaller, config *ConfigJSON, accountJSON *AccountJSON, from common.Address, data *framework.FieldData) (ethereum.CallMsg, *common.Address, *big.Int, error) {
+	msg := ethereum.CallMsg{From: from}
+	txType := data.Get("type").(string)
+	amount, err := ParseAmount(data.Get("amount").(string))
+	if err != nil {
+		return msg, nil, nil, fmt.Errorf("invalid amount: %v", err)
+	}
+	switch txType {
+	case EstimateTransfer, EstimateCall:
+		to, _, err := b.resolveField(ctx, backend, config, data, "to")
+		if err != nil {
+			return msg, nil, nil, err
+		}
+		if err := config.ValidAddress(to); err != nil {
+			return msg, nil, nil, err
+		}
+		if err := accountJSON.ValidAddress(to); err != nil {
+			return msg, nil, nil, err
+		}
+		msg.To = to
+		msg.Value = amount
+		msg.Data = common.FromHex(data.Get("data").(string))
+		return msg, nil, nil, nil
+	case EstimateTokenTransfer:
+		contract, err := config.addressField(data, "contract")
+		if err != nil {
+			return msg, nil, nil, err
+		}
+		to, _, err := b.resolveField(ctx, backend, config, data, "to")
+		if err != nil {
+			return msg, nil, nil, err
+		}
+		if err := config.ValidAddress(to); err != nil {
+			return msg, nil, nil, err
+		}
+		if err := accountJSON.ValidAddress(to); err != nil {
+			return msg, nil, nil, err
+		}
+		instance, err := erc20.NewErc20Caller(contract, backend)
+		if err != nil {
+			return msg, nil, nil, err
+		}
+		decimals, err := instance.Decimals(&bind.CallOpts{Context: ctx})
+		if err != nil {
+			return msg, nil, nil, err
+		}
+		tokens, err := ParseTokenAmount(data.Get("tokens").(string), decimals)
+		if err != nil {
+			return msg, nil, nil, fmt.Errorf("invalid tokens: %v", err)
+		}
+		parsed, err := abi.JSON(strings.NewReader(erc20.Erc20ABI))
+		if err != nil {
+			return msg, nil, nil, err
+		}
+		msg.Data, err = parsed.Pack("transfer", *to, tokens)
+		if err != nil {
+			return msg, nil, nil, err
+		}
+		msg.To = &contract
+		return msg, &contract, tokens, nil
+	case EstimateDeploy:
+		msg.Value = amount
+		msg.Data = common.FromHex(data.Get("data").(string))
+		if len(msg.Data) == 0 {
+			return msg, nil, nil, fmt.Errorf("data is required to deploy a contract")
+		}
+		return msg, nil, nil, nil
+	}
+	return msg, nil, nil, fmt.Errorf("type must be %s, %s, %s or %s", EstimateTransfer, EstimateTokenTransfer, EstimateCall, EstimateDeploy)
+}
+
+func (b *PluginBackend) pathEstimate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
+	if err != nil {
+		return nil, err
+	}
+	_, account, err := getWalletAndAccount(*accountJSON)
+	if err != nil {
+		return nil, err
+	}
+	from := account.Address
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, contract, tokens, err := b.estimateMessage(ctx, client, config, accountJSON, from, data)
+	if err != nil {
+		return nil, err
+	}
+	gasLimit, err := b.gasLimit(ctx, client, config, accountJSON, data, msg)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := b.gasPrice(ctx, config, accountJSON, data)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := b.feeEstimate(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	value := msg.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	var tx *types.Transaction
+	if msg.To != nil {
+		tx = types.NewTransaction(nonce, *msg.To, value, gasLimit, gasPrice, msg.Data)
+	} else {
+		tx = types.NewContractCreation(nonce, value, gasLimit, gasPrice, msg.Data)
+	}
+	l1DataFee, err := L1DataFee(ctx, client, config.ChainID, tx)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := client.BalanceAt(ctx, from, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := strings.ToLower(data.Get("currency").(string))
+	if currency == Empty {
+		currency = config.PriceOracle.currencies()[0]
+	}
+	if !util.Contains(config.PriceOracle.currencies(), currency) {
+		return nil, fmt.Errorf("%s is not one of the currencies of this mount", currency)
+	}
+	c := &converter{b: b, config: config}
+	response := &logical.Response{
+		Data: map[string]interface{}{
+			"type":       data.Get("type").(string),
+			"from":       from.Hex(),
+			"amount":     value.String(),
+			"gas_limit":  gasLimit,
+			"fee_source": fees.Source,
+			"balance":    balance.String(),
+			"currency":   currency,
+		},
+	}
+	if msg.To != nil {
+		response.Data["to"] = msg.To.Hex()
+	}
+	if fees.BaseFee != nil {
+		response.Data["base_fee"] = fees.BaseFee.String()
+	}
+	if l1DataFee != nil {
+		response.Data["l1_data_fee"] = l1DataFee.String()
+	}
+
+	// fee values the fee at a gas price, and says whether the balance covers it
+	fee := func(gasPrice *big.Int) map[string]interface{} {
+		wei := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasLimit))
+		if l1DataFee != nil {
+			wei.Add(wei, l1DataFee)
+		}
+		total := new(big.Int).Add(wei, value)
+		amount := decimal.NewFromBigInt(wei, 0)
+		result := map[string]interface{}{
+			"gas_price":  gasPrice.String(),
+			"fee_wei":    wei.String(),
+			"fee_gwei":   ConvertFromWei(GWEI, amount).String(),
+			"fee_ether":  ConvertFromWei(ETH, amount).String(),
+			"total_cost": total.String(),
+			"covered":    balance.Cmp(total) >= 0,
+		}
+		if fiat, err := c.convert(ctx, &convertUnit{Name: WEI, Ether: WEI}, &convertUnit{Name: currency, Currency: currency}, amount); err == nil {
+			result["fee_"+currency] = fiat.Round(2).String()
+		}
+		return result
+	}
+	tiers := make(map[string]interface{})
+	for _, tier := range feeTiers {
+		tiers[tier.Name] = fee(fees.GasPrices[tier.Name])
+	}
+	response.Data["tiers"] = tiers
+
+	selected := fee(gasPrice)
+	for field, result := range selected {
+		response.Data[field] = result
+	}
+	if _, ok := selected["fee_"+currency]; !ok {
+		response.AddWarning(fmt.Sprintf("fees could not be valued in %s", currency))
+	} else if len(c.prices) > 0 {
+		response.Data["price_source"] = c.prices[0].Source
+		response.Data["price_timestamp"] = c.prices[0].Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	if contract != nil {
+		instance, err := erc20.NewErc20Caller(*contract, client)
+		if err != nil {
+			return nil, err
+		}
+		tokenBalance, err := instance.BalanceOf(&bind.CallOpts{Context: ctx}, from)
+		if err != nil {
+			return nil, err
+		}
+		response.Data["contract"] = contract.Hex()
+		response.Data["tokens"] = tokens.String()
+		response.Data["token_balance"] = tokenBalance.String()
+		response.Data["tokens_covered"] = tokenBalance.Cmp(tokens) >= 0
+	}
+	return response, nil
+}
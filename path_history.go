@@ -0,0 +1,172 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+)
+
+// HistoryJSON is a transaction that was sent from an account
+type HistoryJSON struct {
+	TransactionHash   string `json:"transaction_hash"`
+	Network           string `json:"network"`
+	ChainID           string `json:"chain_id"`
+	From              string `json:"from"`
+	To                string `json:"to"`
+	Amount            string `json:"amount"`
+	Nonce             uint64 `json:"nonce"`
+	GasPrice          string `json:"gas_price"`
+	GasLimit          uint64 `json:"gas_limit"`
+	SignedTransaction string `json:"signed_transaction"`
+	Source            string `json:"source"`
+	SentAt            string `json:"sent_at"`
+}
+
+func historyPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: QualifiedPath("accounts/" + framework.GenericNameRegex("name") + "/history/?"),
+			Fields: map[string]*framework.FieldSchema{
+				"name": {Type: framework.TypeString},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.pathHistoryList,
+			},
+			HelpSynopsis: "List the transactions sent from an account",
+			HelpDescription: `
+			The hashes of the transactions sent from the account will be listed.
+			`,
+		},
+		{
+			Pattern:      QualifiedPath("accounts/" + framework.GenericNameRegex("name") + "/history/" + framework.GenericNameRegex("transaction_hash")),
+			HelpSynopsis: "Read a transaction sent from an account.",
+			HelpDescription: `
+
+Reads the record of a transaction sent from the account, including the signed
+transaction as it was broadcast. Every path that sends a transaction records it,
+and the source is the path that sent it: transfer, deploy, signer, broadcast or a
+contract path such as erc20/transfer or safe/execute.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":             {Type: framework.TypeString},
+				"transaction_hash": {Type: framework.TypeString},
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: b.pathHistoryRead,
+			},
+		},
+	}
+}
+
+func historyPath(name string, hash string) string {
+	return QualifiedPath(fmt.Sprintf("history/%s/%s", name, strings.ToLower(hash)))
+}
+
+func (b *PluginBackend) pathHistoryList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	_, err := b.configured(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	vals, err := req.Storage.List(ctx, QualifiedPath(fmt.Sprintf("history/%s/", name)))
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(vals), nil
+}
+
+func (b *PluginBackend) pathHistoryRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	_, err := b.configured(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	path := historyPath(data.Get("name").(string), data.Get("transaction_hash").(string))
+	entry, err := req.Storage.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var history HistoryJSON
+	if err := entry.DecodeJSON(&history); err != nil {
+		return nil, fmt.Errorf("failed to deserialize history at %s", path)
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"transaction_hash":   history.TransactionHash,
+			"network":            history.Network,
+			"chain_id":           history.ChainID,
+			"from":               history.From,
+			"to":                 history.To,
+			"amount":             history.Amount,
+			"nonce":              history.Nonce,
+			"gas_price":          history.GasPrice,
+			"gas_limit":          history.GasLimit,
+			"signed_transaction": history.SignedTransaction,
+			"source":             history.Source,
+			"sent_at":            history.SentAt,
+		},
+	}, nil
+}
+
+// recordHistory links a transaction that was sent into the history of an account
+func recordHistory(ctx context.Context, req *logical.Request, name string, history *HistoryJSON) error {
+	entry, err := logical.StorageEntryJSON(historyPath(name, history.TransactionHash), history)
+	if err != nil {
+		return err
+	}
+	return req.Storage.Put(ctx, entry)
+}
+
+// recordTransaction links a transaction that an account sent into its history. The
+// source is the path that sent it.
+func recordTransaction(ctx context.Context, req *logical.Request, config *ConfigJSON, name string, from common.Address, tx *types.Transaction, source string) (*HistoryJSON, error) {
+	var signedTxBuff bytes.Buffer
+	if err := tx.EncodeRLP(&signedTxBuff); err != nil {
+		return nil, err
+	}
+	history := &HistoryJSON{
+		TransactionHash:   tx.Hash().Hex(),
+		Network:           config.Network,
+		ChainID:           config.ChainID,
+		From:              from.Hex(),
+		Amount:            tx.Value().String(),
+		Nonce:             tx.Nonce(),
+		GasPrice:          tx.GasPrice().String(),
+		GasLimit:          tx.Gas(),
+		SignedTransaction: hexutil.Encode(signedTxBuff.Bytes()),
+		Source:            source,
+		SentAt:            time.Now().UTC().Format(time.RFC3339),
+	}
+	if tx.To() != nil {
+		history.To = tx.To().Hex()
+	}
+	if err := recordHistory(ctx, req, name, history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
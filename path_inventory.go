@@ -0,0 +1,357 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/contracts/erc165"
+	"github.com/immutability-io/vault-ethereum/contracts/erc721"
+)
+
+const (
+	// InventoryEnumerable means the inventory was read through ERC721Enumerable
+	InventoryEnumerable string = "enumerable"
+	// InventoryEvents means the inventory was rebuilt by replaying Transfer events
+	InventoryEvents string = "events"
+	// DefaultBlockRange is the number of blocks requested per eth_getLogs call
+	DefaultBlockRange int = 5000
+	// DefaultMaxBlocks is the number of blocks scanned per inventory request
+	DefaultMaxBlocks int = 500000
+)
+
+// erc721EnumerableInterface is the ERC-165 identifier for ERC721Enumerable
+var erc721EnumerableInterface = [4]byte{0x78, 0x0e, 0x9d, 0x63}
+
+// InventoryJSON is the scanning checkpoint for a collection without ERC721Enumerable
+type InventoryJSON struct {
+	Owner     string   `json:"owner"`
+	NextBlock uint64   `json:"next_block"`
+	TokenIDs  []string `json:"token_ids"`
+}
+
+func inventoryPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      ContractPath(erc721Contract, "inventory"),
+			HelpSynopsis: "Returns every token ID owned in an NFT collection.",
+			HelpDescription: `
+
+Returns every token ID owned in an ERC-721 collection. If the collection reports
+ERC721Enumerable through ERC-165, the tokens are read directly. Otherwise the
+collection's Transfer events are replayed. The scan is checkpointed per collection,
+so a large history can be worked through over several requests: keep writing to
+this path until complete is true. Reading it returns the checkpoint without
+scanning.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"contract": {
+					Type:        framework.TypeString,
+					Description: "The address of the ERC-721 NFT.",
+				},
+				"owner": {
+					Type:        framework.TypeString,
+					Description: "The address that owns the NFTs - defaults to this account.",
+				},
+				"from_block": {
+					Type:        framework.TypeInt,
+					Description: "The block to start replaying events from when there is no checkpoint.",
+					Default:     0,
+				},
+				"block_range": {
+					Type:        framework.TypeInt,
+					Description: "The number of blocks to request logs for at a time.",
+					Default:     DefaultBlockRange,
+				},
+				"max_blocks": {
+					Type:        framework.TypeInt,
+					Description: "The maximum number of blocks to scan in this request.",
+					Default:     DefaultMaxBlocks,
+				},
+				"reset": {
+					Type:        framework.TypeBool,
+					Description: "Discard the checkpoint and scan again from from_block.",
+					Default:     false,
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathERC721Inventory,
+				logical.CreateOperation: b.pathERC721Inventory,
+				logical.UpdateOperation: b.pathERC721Inventory,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathERC721Inventory(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	// Checkpoints are kept per account, so this also checks that the account exists
+	// when the owner is someone else
+	owner, err := b.ownerOrSelf(ctx, req, config, data)
+	if err != nil {
+		return nil, err
+	}
+	tokenAddress, err := config.addressField(data, "contract")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if supportsInterface(client, tokenAddress, erc721EnumerableInterface) {
+		tokenIDs, err := enumerateInventory(client, tokenAddress, *owner)
+		if err != nil {
+			return nil, err
+		}
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"contract":  tokenAddress.Hex(),
+				"owner":     owner.Hex(),
+				"method":    InventoryEnumerable,
+				"token_ids": tokenIDs,
+				"count":     len(tokenIDs),
+				"complete":  true,
+			},
+		}, nil
+	}
+
+	blockRange := data.Get("block_range").(int)
+	maxBlocks := data.Get("max_blocks").(int)
+	if blockRange <= 0 || maxBlocks <= 0 {
+		return nil, fmt.Errorf("block_range and max_blocks must be positive")
+	}
+
+	path := inventoryPath(name, config.Network, tokenAddress, *owner)
+	inventory, err := readInventory(ctx, req, path)
+	if err != nil {
+		return nil, err
+	}
+	if inventory == nil || data.Get("reset").(bool) {
+		fromBlock := data.Get("from_block").(int)
+		if fromBlock < 0 {
+			return nil, fmt.Errorf("from_block can't be negative")
+		}
+		inventory = &InventoryJSON{
+			Owner:     owner.Hex(),
+			NextBlock: uint64(fromBlock),
+		}
+	}
+
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	latestBlock := header.Number.Uint64()
+
+	instance, err := erc721.NewErc721Filterer(tokenAddress, client)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reads don't write storage, so only updates advance the scan
+	scanned := uint64(0)
+	for req.Operation != logical.ReadOperation && inventory.NextBlock <= latestBlock && scanned < uint64(maxBlocks) {
+		end := inventory.NextBlock + uint64(blockRange) - 1
+		if end > latestBlock {
+			end = latestBlock
+		}
+		err = replayTransfers(ctx, instance, inventory, *owner, end)
+		if err != nil {
+			return nil, err
+		}
+		scanned += end - inventory.NextBlock + 1
+		inventory.NextBlock = end + 1
+
+		// Checkpoint after every range so a failure doesn't lose the work done so far
+		entry, err := logical.StorageEntryJSON(path, inventory)
+		if err != nil {
+			return nil, err
+		}
+		if err := req.Storage.Put(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &logical.Response{
+		Data: map[string]interface{}{
+			"contract":            tokenAddress.Hex(),
+			"owner":               owner.Hex(),
+			"method":              InventoryEvents,
+			"token_ids":           inventory.TokenIDs,
+			"count":               len(inventory.TokenIDs),
+			"latest_block":        latestBlock,
+			"complete":            inventory.NextBlock > latestBlock,
+			"blocks_this_request": scanned,
+		},
+	}
+	if inventory.NextBlock > 0 {
+		response.Data["scanned_to_block"] = inventory.NextBlock - 1
+	}
+	return response, nil
+}
+
+// supportsInterface asks a contract through ERC-165; a contract that can't answer doesn't support it
+func supportsInterface(client *ethclient.Client, contract common.Address, interfaceID [4]byte) bool {
+	instance, err := erc165.NewErc165Caller(contract, client)
+	if err != nil {
+		return false
+	}
+	supported, err := instance.SupportsInterface(&bind.CallOpts{}, interfaceID)
+	if err != nil {
+		return false
+	}
+	return supported
+}
+
+func enumerateInventory(client *ethclient.Client, contract common.Address, owner common.Address) ([]string, error) {
+	instance, err := erc721.NewErc721Caller(contract, client)
+	if err != nil {
+		return nil, err
+	}
+	tokenSession := &erc721.Erc721CallerSession{
+		Contract: instance,
+		CallOpts: bind.CallOpts{},
+	}
+	balance, err := tokenSession.BalanceOf(owner)
+	if err != nil {
+		return nil, err
+	}
+	tokenIDs := []string{}
+	for index := big.NewInt(0); index.Cmp(balance) < 0; index = new(big.Int).Add(index, big.NewInt(1)) {
+		tokenID, err := tokenSession.TokenOfOwnerByIndex(owner, index)
+		if err != nil {
+			return nil, err
+		}
+		tokenIDs = append(tokenIDs, tokenID.String())
+	}
+	return tokenIDs, nil
+}
+
+// replayTransfers applies the Transfer events in [inventory.NextBlock, end] to the inventory
+func replayTransfers(ctx context.Context, instance *erc721.Erc721Filterer, inventory *InventoryJSON, owner common.Address, end uint64) error {
+	opts := &bind.FilterOpts{
+		Start:   inventory.NextBlock,
+		End:     &end,
+		Context: ctx,
+	}
+	var logs []types.Log
+	received, err := instance.FilterTransfer(opts, nil, []common.Address{owner}, nil)
+	if err != nil {
+		return err
+	}
+	for received.Next() {
+		logs = append(logs, received.Event.Raw)
+	}
+	if err := received.Error(); err != nil {
+		return err
+	}
+	sent, err := instance.FilterTransfer(opts, []common.Address{owner}, nil, nil)
+	if err != nil {
+		return err
+	}
+	for sent.Next() {
+		// A transfer to self shows up in both filters
+		if sent.Event.To != owner {
+			logs = append(logs, sent.Event.Raw)
+		}
+	}
+	if err := sent.Error(); err != nil {
+		return err
+	}
+
+	// Order matters: a token can be received and sent on again within the same range
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	held := make(map[string]bool)
+	for _, tokenID := range inventory.TokenIDs {
+		held[tokenID] = true
+	}
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) != 4 {
+			continue
+		}
+		from := common.BytesToAddress(log.Topics[1].Bytes())
+		to := common.BytesToAddress(log.Topics[2].Bytes())
+		tokenID := log.Topics[3].Big().String()
+		if from == owner {
+			delete(held, tokenID)
+		}
+		if to == owner {
+			held[tokenID] = true
+		}
+	}
+
+	tokenIDs := []string{}
+	for tokenID := range held {
+		tokenIDs = append(tokenIDs, tokenID)
+	}
+	sort.Slice(tokenIDs, func(i, j int) bool {
+		a, _ := new(big.Int).SetString(tokenIDs[i], 10)
+		b, _ := new(big.Int).SetString(tokenIDs[j], 10)
+		return a.Cmp(b) < 0
+	})
+	inventory.TokenIDs = tokenIDs
+	return nil
+}
+
+// inventoryPath keeps the checkpoints of each named network apart, since the same
+// contract address can hold a different collection on another chain
+func inventoryPath(name string, network string, contract common.Address, owner common.Address) string {
+	if network != Empty {
+		return QualifiedPath(fmt.Sprintf("inventory/%s/networks/%s/%s/%s", name, network, contract.Hex(), owner.Hex()))
+	}
+	return QualifiedPath(fmt.Sprintf("inventory/%s/%s/%s", name, contract.Hex(), owner.Hex()))
+}
+
+func readInventory(ctx context.Context, req *logical.Request, path string) (*InventoryJSON, error) {
+	entry, err := req.Storage.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var inventory InventoryJSON
+	if err := entry.DecodeJSON(&inventory); err != nil {
+		return nil, fmt.Errorf("failed to deserialize inventory at %s", path)
+	}
+	return &inventory, nil
+}
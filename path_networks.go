@@ -0,0 +1,324 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+// NetworkJSON is a named network a mount can send requests to
+type NetworkJSON struct {
+	ChainID    string      `json:"chain_id"`
+	RPCURLs    []string    `json:"rpc_urls"`
+	Inclusions []string    `json:"inclusions"`
+	Exclusions []string    `json:"exclusions"`
+	RPCAuth    RPCAuthJSON `json:"rpc_auth"`
+	// CoinGeckoID, PriceFeeds and StaticPrices price the network's native asset in
+	// place of the mount's settings, which are for the mount's own chain
+	CoinGeckoID  string            `json:"coingecko_id"`
+	PriceFeeds   map[string]string `json:"price_feeds"`
+	StaticPrices map[string]string `json:"static_prices"`
+	// ENSRegistry and BundlerURL replace the mount's, which are for the mount's own chain
+	ENSRegistry string `json:"ens_registry"`
+	BundlerURL  string `json:"bundler_url"`
+
+	addressBook addressBook
+}
+
+// ValidAddress returns an error if the address is not included or if it is excluded
+func (network *NetworkJSON) ValidAddress(toAddress *common.Address) error {
+	if err := network.addressBook.excludes(network.Exclusions, toAddress, "network"); err != nil {
+		return err
+	}
+
+	if len(network.Inclusions) > 0 && !network.addressBook.contains(network.Inclusions, toAddress) {
+		return fmt.Errorf("%s is not in the set of inclusions of this network", toAddress.Hex())
+	}
+	return nil
+}
+
+// networkField is the field every request that talks to a chain accepts
+func networkField() *framework.FieldSchema {
+	return &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "The named network to use - defaults to the mount's default_network.",
+	}
+}
+
+func networkPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: QualifiedPath("config/networks/?"),
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.pathNetworksList,
+			},
+			HelpSynopsis: "List the networks configured on this mount",
+			HelpDescription: `
+			All the named networks will be listed.
+			`,
+		},
+		{
+			Pattern:      QualifiedPath("config/networks/" + framework.GenericNameRegex("network")),
+			HelpSynopsis: "Configure a named network.",
+			HelpDescription: `
+
+Configures a network that requests can select with the network parameter. Each
+network has its own chain ID, RPC URLs and inclusions and exclusions, which apply
+in addition to those of the mount.
+
+The native asset of a network is priced with its own coingecko_id, price_feeds and
+static_prices rather than the mount's. Without a coingecko_id, the coin of a chain
+in the registry is used. The mount's price_source, TTL and currencies still apply.
+
+ENS names are resolved through the network's ens_registry, which defaults to the
+registry on mainnet and the public testnets. User operations are submitted to the
+network's bundler_url; the mount's bundler_url is not used on other networks.
+
+`,
+			Fields: withRPCAuthFields(withAssetPriceFields(map[string]*framework.FieldSchema{
+				"network": {Type: framework.TypeString},
+				"chain_id": {
+					Type:        framework.TypeString,
+					Description: chainIDDescription(),
+				},
+				"rpc_urls": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The RPC addresses of the network.",
+				},
+				"inclusions": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Only these accounts may be transacted with on this network.",
+				},
+				"exclusions": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "These accounts can never be transacted with on this network.",
+				},
+				"ens_registry": {
+					Type:        framework.TypeString,
+					Description: "The address of the ENS registry on this network.",
+				},
+				"bundler_url": {
+					Type:        framework.TypeString,
+					Description: "The RPC address of the ERC-4337 bundler for this network.",
+				},
+				"force": {
+					Type:        framework.TypeBool,
+					Description: "Save the network even if a node can't be reached or reports a different chain ID.",
+					Default:     false,
+				},
+			})),
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathNetworksRead,
+				logical.CreateOperation: b.pathNetworksWrite,
+				logical.UpdateOperation: b.pathNetworksWrite,
+				logical.DeleteOperation: b.pathNetworksDelete,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathNetworksList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	vals, err := req.Storage.List(ctx, QualifiedPath("networks/"))
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(vals), nil
+}
+
+func (b *PluginBackend) pathNetworksRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("network").(string)
+	network, err := readNetwork(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if network == nil {
+		return nil, nil
+	}
+	return networkResponse(network), nil
+}
+
+func (b *PluginBackend) pathNetworksWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("network").(string)
+	network, err := readNetwork(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if network == nil {
+		network = &NetworkJSON{}
+	}
+	if chainID, ok := data.GetOk("chain_id"); ok {
+		network.ChainID = ResolveChainID(chainID.(string))
+	}
+	if err := readRPCAuth(data, &network.RPCAuth); err != nil {
+		return nil, err
+	}
+	if rpcURLs, ok := data.GetOk("rpc_urls"); ok {
+		network.RPCURLs = nil
+		for _, rpcURL := range rpcURLs.([]string) {
+			rpcURL, err = withoutCredentials(rpcURL, &network.RPCAuth)
+			if err != nil {
+				return nil, err
+			}
+			network.RPCURLs = append(network.RPCURLs, rpcURL)
+		}
+	}
+	if inclusions, ok := data.GetOk("inclusions"); ok {
+		network.Inclusions = inclusions.([]string)
+	}
+	if exclusions, ok := data.GetOk("exclusions"); ok {
+		network.Exclusions = exclusions.([]string)
+	}
+	if err := validAddressBookReferences(ctx, req.Storage, network.Inclusions, network.Exclusions); err != nil {
+		return nil, err
+	}
+	if ensRegistry, ok := data.GetOk("ens_registry"); ok {
+		if ensRegistry.(string) != Empty {
+			if _, err := util.ParseAddress(ensRegistry.(string), false); err != nil {
+				return nil, fmt.Errorf("invalid ens_registry: %v", err)
+			}
+		}
+		network.ENSRegistry = ensRegistry.(string)
+	}
+	if bundlerURL, ok := data.GetOk("bundler_url"); ok {
+		network.BundlerURL = bundlerURL.(string)
+	}
+	if err := readAssetPrices(data, &network.CoinGeckoID, &network.PriceFeeds, &network.StaticPrices); err != nil {
+		return nil, err
+	}
+	if len(network.RPCURLs) == 0 {
+		return nil, fmt.Errorf("network %s needs at least one rpc_url", name)
+	}
+	network.ChainID, err = verifyChainID(ctx, network.ChainID, network.RPCURLs, &network.RPCAuth, data.Get("force").(bool))
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := logical.StorageEntryJSON(QualifiedPath("networks/"+name), network)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+	b.resetClients(ctx)
+	return networkResponse(network), nil
+}
+
+func (b *PluginBackend) pathNetworksDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("network").(string)
+	// Networks can be configured before the mount is
+	entry, err := req.Storage.Get(ctx, "config")
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		config, err := b.readConfig(ctx, req.Storage)
+		if err != nil {
+			return nil, err
+		}
+		if config.DefaultNetwork == name {
+			return nil, fmt.Errorf("network %s is the default_network of this mount - change default_network first", name)
+		}
+	}
+	if err := req.Storage.Delete(ctx, QualifiedPath("networks/"+name)); err != nil {
+		return nil, err
+	}
+	b.resetClients(ctx)
+	return nil, nil
+}
+
+func networkResponse(network *NetworkJSON) *logical.Response {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"chain_id":   network.ChainID,
+			"chain":      chainResponse(network.ChainID),
+			"rpc_urls":   network.RPCURLs,
+			"inclusions": network.Inclusions,
+			"exclusions": network.Exclusions,
+			"rpc_auth":   network.RPCAuth.Methods(),
+
+			"coingecko_id":  network.CoinGeckoID,
+			"price_feeds":   network.PriceFeeds,
+			"static_prices": network.StaticPrices,
+			"ens_registry":  network.ENSRegistry,
+			"bundler_url":   network.BundlerURL,
+		},
+	}
+}
+
+func readNetwork(ctx context.Context, s logical.Storage, name string) (*NetworkJSON, error) {
+	path := QualifiedPath("networks/" + name)
+	entry, err := s.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var network NetworkJSON
+	if err := entry.DecodeJSON(&network); err != nil {
+		return nil, fmt.Errorf("failed to deserialize network at %s", path)
+	}
+	return &network, nil
+}
+
+// configuredNetwork returns the mount configuration with the network selected by the
+// request applied to it. Without a network parameter the mount's default_network is
+// used, and without that the mount's own rpc_url and chain_id.
+func (b *PluginBackend) configuredNetwork(ctx context.Context, req *logical.Request, data *framework.FieldData) (*ConfigJSON, error) {
+	config, err := b.configured(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	name := config.DefaultNetwork
+	if networkRaw, ok := data.GetOk("network"); ok && networkRaw.(string) != Empty {
+		name = networkRaw.(string)
+	}
+	if name == Empty {
+		return config, nil
+	}
+	network, err := readNetwork(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if network == nil {
+		return nil, fmt.Errorf("network %s does not exist", name)
+	}
+
+	network.addressBook, err = readAddressBook(ctx, req.Storage, network.Inclusions, network.Exclusions)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := *config
+	selected.Network = name
+	selected.ChainID = network.ChainID
+	selected.RPC = network.RPCURLs[0]
+	selected.PriceOracle.CoinGeckoID = network.CoinGeckoID
+	selected.PriceOracle.Feeds = network.PriceFeeds
+	selected.PriceOracle.StaticPrices = network.StaticPrices
+	selected.ENSRegistry = network.ENSRegistry
+	selected.BundlerURL = network.BundlerURL
+	selected.network = network
+	return &selected, nil
+}
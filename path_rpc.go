@@ -0,0 +1,152 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+// rpcMethodPattern matches a JSON-RPC method name
+var rpcMethodPattern = regexp.MustCompile(`^[a-zA-Z0-9]+_[a-zA-Z0-9_]+$`)
+
+// refusedRPCPrefixes are methods that sign, send or manage keys and accounts on the
+// node. They are refused even if they are in the allowlist.
+var refusedRPCPrefixes = []string{
+	"eth_sign",
+	"eth_send",
+	"personal_",
+	"account_",
+	"clef_",
+	"admin_",
+	"miner_",
+	"debug_",
+}
+
+// refusedRPCMethod returns true if a method can never be passed through
+func refusedRPCMethod(method string) bool {
+	for _, prefix := range refusedRPCPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validRPCAllowlist refuses allowlists with methods that aren't JSON-RPC methods or that
+// can never be passed through
+func validRPCAllowlist(methods []string) error {
+	for _, method := range methods {
+		if !rpcMethodPattern.MatchString(method) {
+			return fmt.Errorf("%s is not a JSON-RPC method", method)
+		}
+		if refusedRPCMethod(method) {
+			return fmt.Errorf("%s signs or sends transactions or manages the node and can't be allowed", method)
+		}
+	}
+	return nil
+}
+
+func rpcPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      QualifiedPath("rpc"),
+			HelpSynopsis: "Forward a read-only JSON-RPC call to the node.",
+			HelpDescription: `
+
+Forwards a JSON-RPC call to the node of the selected network, so that applications
+holding a Vault token don't need credentials for the node. Only the methods in the
+mount's rpc_allowlist are forwarded; methods that sign or send transactions or
+manage the node are always refused.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"network": networkField(),
+				"method": {
+					Type:        framework.TypeString,
+					Description: "The JSON-RPC method, such as eth_getTransactionReceipt.",
+				},
+				"params": {
+					Type:        framework.TypeSlice,
+					Description: "The parameters of the call.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathRPC,
+				logical.UpdateOperation: b.pathRPC,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathRPC(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	method := data.Get("method").(string)
+	result, err := b.forwardRPC(ctx, config, method, data.Get("params").([]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"network": config.Network,
+			"method":  method,
+			"result":  result,
+		},
+	}, nil
+}
+
+// forwardRPC forwards a call to the node if the method is in the mount's allowlist
+func (b *PluginBackend) forwardRPC(ctx context.Context, config *ConfigJSON, method string, params []interface{}) (interface{}, error) {
+	if method == Empty {
+		return nil, fmt.Errorf("method is required")
+	}
+	if refusedRPCMethod(method) {
+		return nil, fmt.Errorf("%s can't be called through this mount", method)
+	}
+	if !util.Contains(config.RPCAllowlist, method) {
+		return nil, fmt.Errorf("%s is not in the rpc_allowlist of this mount", method)
+	}
+
+	client, err := b.rpcClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	if err := client.CallContext(ctx, &raw, method, params...); err != nil {
+		return nil, err
+	}
+	// Numbers are kept as they are rather than rounded to floats
+	var result interface{}
+	if len(raw) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,600 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/contracts/safe"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+const (
+	safeContract string = "safe"
+	// SafeOperationCall is a regular call from the Safe
+	SafeOperationCall uint8 = 0
+	// SafeOperationDelegateCall runs the target's code in the Safe's context
+	SafeOperationDelegateCall uint8 = 1
+)
+
+var (
+	// safeTxTypeHash is keccak256("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)")
+	safeTxTypeHash = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+	// safeDomainTypeHash is the domain used by Safe 1.3.0 and later
+	safeDomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	// safeLegacyDomainTypeHash is the domain used before Safe 1.3.0, which didn't bind the chain ID
+	safeLegacyDomainTypeHash = crypto.Keccak256Hash([]byte("EIP712Domain(address verifyingContract)"))
+)
+
+// SafeTx is a Gnosis Safe transaction as it is hashed and signed by the owners
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+// DomainSeparator returns the EIP-712 domain separator of a Safe
+func DomainSeparator(safeAddress common.Address, chainID *big.Int, version string) common.Hash {
+	if legacySafeVersion(version) {
+		return crypto.Keccak256Hash(safeLegacyDomainTypeHash.Bytes(), common.LeftPadBytes(safeAddress.Bytes(), 32))
+	}
+	return crypto.Keccak256Hash(
+		safeDomainTypeHash.Bytes(),
+		abiUint256(chainID),
+		common.LeftPadBytes(safeAddress.Bytes(), 32),
+	)
+}
+
+// Hash returns the EIP-712 SafeTx hash that the owners sign
+func (tx *SafeTx) Hash(domainSeparator common.Hash) common.Hash {
+	structHash := crypto.Keccak256Hash(
+		safeTxTypeHash.Bytes(),
+		common.LeftPadBytes(tx.To.Bytes(), 32),
+		abiUint256(tx.Value),
+		crypto.Keccak256(tx.Data),
+		common.LeftPadBytes([]byte{tx.Operation}, 32),
+		abiUint256(tx.SafeTxGas),
+		abiUint256(tx.BaseGas),
+		abiUint256(tx.GasPrice),
+		common.LeftPadBytes(tx.GasToken.Bytes(), 32),
+		common.LeftPadBytes(tx.RefundReceiver.Bytes(), 32),
+		abiUint256(tx.Nonce),
+	)
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator.Bytes(), structHash.Bytes())
+}
+
+// legacySafeVersion returns true for Safe versions before 1.3.0
+func legacySafeVersion(version string) bool {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 {
+		return false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	return major < 1 || (major == 1 && minor < 3)
+}
+
+func safeTxFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"name":    {Type: framework.TypeString},
+		"network": networkField(),
+		"safe": {
+			Type:        framework.TypeString,
+			Description: "The address of the Safe.",
+		},
+		"to": {
+			Type:        framework.TypeString,
+			Description: "The address or ENS name the Safe calls.",
+		},
+		"value": {
+			Type:        framework.TypeString,
+			Description: "Amount of ETH the Safe sends - wei, 0x hex wei or a number and a unit, such as 1.5 ether.",
+			Default:     "0",
+		},
+		"data": {
+			Type:        framework.TypeString,
+			Description: "The hex encoded calldata of the Safe's call.",
+		},
+		"operation": {
+			Type:        framework.TypeInt,
+			Description: "0 for a call, 1 for a delegatecall. Delegatecalls are refused.",
+			Default:     0,
+		},
+		"safe_tx_gas": {
+			Type:        framework.TypeString,
+			Description: "Gas the Safe forwards to the call.",
+			Default:     "0",
+		},
+		"base_gas": {
+			Type:        framework.TypeString,
+			Description: "Gas costs independent of the call, used for the refund.",
+			Default:     "0",
+		},
+		"refund_gas_price": {
+			Type:        framework.TypeString,
+			Description: "Gas price used for the refund - 0 means no refund.",
+			Default:     "0",
+		},
+		"gas_token": {
+			Type:        framework.TypeString,
+			Description: "Token used for the refund - the zero address means ETH.",
+			Default:     util.ZeroAddress,
+		},
+		"refund_receiver": {
+			Type:        framework.TypeString,
+			Description: "Address that receives the refund - the zero address means tx.origin.",
+			Default:     util.ZeroAddress,
+		},
+		"nonce": {
+			Type:        framework.TypeString,
+			Description: "The Safe nonce - defaults to the Safe's current nonce.",
+		},
+	}
+}
+
+func safePaths(b *PluginBackend) []*framework.Path {
+	executeFields := safeTxFields()
+	executeFields["signatures"] = &framework.FieldSchema{
+		Type:        framework.TypeCommaStringSlice,
+		Description: "Hex encoded signatures of the other owners.",
+	}
+	executeFields["sign"] = &framework.FieldSchema{
+		Type:        framework.TypeBool,
+		Description: "Add this account's signature if it is an owner.",
+		Default:     true,
+	}
+	executeFields["gas_limit"] = gasLimitField()
+	return []*framework.Path{
+		{
+			Pattern:      ContractPath(safeContract, "sign"),
+			HelpSynopsis: "Sign a Safe transaction as one of its owners.",
+			HelpDescription: `
+
+Builds a SafeTx for a Gnosis Safe, fetching its nonce on-chain if none is given,
+computes the EIP-712 SafeTx hash, and signs it with this account, which must be
+an owner of the Safe. The signature can be collected with those of the other
+owners and passed to the execute path.
+
+`,
+			Fields:         safeTxFields(),
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathSafeSign,
+				logical.UpdateOperation: b.pathSafeSign,
+			},
+		},
+		{
+			Pattern:      ContractPath(safeContract, "execute"),
+			HelpSynopsis: "Execute a Safe transaction once enough owners have signed.",
+			HelpDescription: `
+
+Calls execTransaction on a Gnosis Safe from this account. The signatures of the
+other owners are ordered as the Safe requires, and this account's own signature
+is added if it is an owner. The transaction is refused unless the Safe's
+threshold is met.
+
+`,
+			Fields:         executeFields,
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathSafeExecute,
+				logical.UpdateOperation: b.pathSafeExecute,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathSafeSign(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
+	if err != nil {
+		return nil, err
+	}
+	wallet, account, err := getWalletAndAccount(*accountJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	safeSession, safeAddress, err := safeCallerSession(client, config, data)
+	if err != nil {
+		return nil, err
+	}
+
+	isOwner, err := safeSession.IsOwner(account.Address)
+	if err != nil {
+		return nil, err
+	}
+	if !isOwner {
+		return nil, fmt.Errorf("%s is not an owner of the Safe %s", account.Address.Hex(), safeAddress.Hex())
+	}
+
+	safeTx, toName, err := b.safeTransaction(ctx, client, config, accountJSON, safeSession, data)
+	if err != nil {
+		return nil, err
+	}
+	safeTxHash, err := safeTransactionHash(config, safeSession, safeAddress, safeTx)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := wallet.SignHash(*account, safeTxHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+
+	response := safeTxResponse(safeAddress, safeTx)
+	response.Data["safe_tx_hash"] = safeTxHash.Hex()
+	response.Data["signature"] = hexutil.Encode(signature)
+	response.Data["owner"] = account.Address.Hex()
+	return withENSName(response, "to", toName), nil
+}
+
+func (b *PluginBackend) pathSafeExecute(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
+	if err != nil {
+		return nil, err
+	}
+	wallet, account, err := getWalletAndAccount(*accountJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := util.ValidNumber(config.ChainID)
+	if chainID == nil {
+		return nil, fmt.Errorf("invalid chain ID")
+	}
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	safeSession, safeAddress, err := safeCallerSession(client, config, data)
+	if err != nil {
+		return nil, err
+	}
+
+	safeTx, toName, err := b.safeTransaction(ctx, client, config, accountJSON, safeSession, data)
+	if err != nil {
+		return nil, err
+	}
+	currentNonce, err := safeSession.Nonce()
+	if err != nil {
+		return nil, err
+	}
+	if safeTx.Nonce.Cmp(currentNonce) != 0 {
+		return nil, fmt.Errorf("the Safe's nonce is %s, so a transaction with nonce %s can't be executed", currentNonce, safeTx.Nonce)
+	}
+	safeTxHash, err := safeTransactionHash(config, safeSession, safeAddress, safeTx)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := safeSession.GetOwners()
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := safeSession.GetThreshold()
+	if err != nil {
+		return nil, err
+	}
+	isOwner := make(map[common.Address]bool)
+	for _, owner := range owners {
+		isOwner[owner] = true
+	}
+
+	signatures := make(map[common.Address][]byte)
+	for _, encoded := range data.Get("signatures").([]string) {
+		signature, err := hexutil.Decode(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %s: %v", encoded, err)
+		}
+		signer, err := safeSigner(safeTxHash, signature)
+		if err != nil {
+			return nil, err
+		}
+		if !isOwner[*signer] {
+			return nil, fmt.Errorf("%s signed the transaction but is not an owner of the Safe", signer.Hex())
+		}
+		signatures[*signer] = signature
+	}
+	if _, ok := signatures[account.Address]; !ok && data.Get("sign").(bool) && isOwner[account.Address] {
+		signature, err := wallet.SignHash(*account, safeTxHash.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		signature[crypto.RecoveryIDOffset] += 27
+		signatures[account.Address] = signature
+	}
+	if big.NewInt(int64(len(signatures))).Cmp(threshold) < 0 {
+		return nil, fmt.Errorf("the Safe needs %s signatures but only %d were provided", threshold, len(signatures))
+	}
+
+	// The Safe requires signatures ordered by owner address
+	var signers []common.Address
+	for signer := range signatures {
+		signers = append(signers, signer)
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i].Bytes(), signers[j].Bytes()) < 0
+	})
+	var packed []byte
+	var signerList []string
+	for _, signer := range signers {
+		packed = append(packed, signatures[signer]...)
+		signerList = append(signerList, signer.Hex())
+	}
+
+	instance, err := safe.NewGnosisSafe(*safeAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	transactOpts, err := b.NewWalletTransactor(chainID, wallet, account)
+	if err != nil {
+		return nil, err
+	}
+	transactOpts, err = b.withFeePolicy(ctx, client, config, accountJSON, data, transactOpts, &contractCall{
+		contract: *safeAddress,
+		abi:      safe.GnosisSafeABI,
+		method:   "execTransaction",
+		args:     []interface{}{safeTx.To, safeTx.Value, safeTx.Data, safeTx.Operation, safeTx.SafeTxGas, safeTx.BaseGas, safeTx.GasPrice, safeTx.GasToken, safeTx.RefundReceiver, packed},
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx, err := instance.ExecTransaction(transactOpts, safeTx.To, safeTx.Value, safeTx.Data, safeTx.Operation, safeTx.SafeTxGas, safeTx.BaseGas, safeTx.GasPrice, safeTx.GasToken, safeTx.RefundReceiver, packed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := recordTransaction(ctx, req, config, name, account.Address, tx, safeContract+"/execute"); err != nil {
+		return nil, err
+	}
+
+	var signedTxBuff bytes.Buffer
+	tx.EncodeRLP(&signedTxBuff)
+
+	response := safeTxResponse(safeAddress, safeTx)
+	response.Data["safe_tx_hash"] = safeTxHash.Hex()
+	response.Data["signers"] = signerList
+	response.Data["transaction_hash"] = tx.Hash().Hex()
+	response.Data["signed_transaction"] = hexutil.Encode(signedTxBuff.Bytes())
+	response.Data["from"] = account.Address.Hex()
+	response.Data["gas_price"] = tx.GasPrice()
+	response.Data["gas_limit"] = tx.Gas()
+	return withENSName(response, "to", toName), nil
+}
+
+// safeTransaction builds the SafeTx from the request and applies the mount and account policy to it
+func (b *PluginBackend) safeTransaction(ctx context.Context, client *ethclient.Client, config *ConfigJSON, accountJSON *AccountJSON, safeSession *safe.GnosisSafeCallerSession, data *framework.FieldData) (*SafeTx, string, error) {
+	to, toName, err := b.resolveField(ctx, client, config, data, "to")
+	if err != nil {
+		return nil, Empty, err
+	}
+	operation := data.Get("operation").(int)
+	if operation != int(SafeOperationCall) {
+		// A delegatecall runs arbitrary code as the Safe, so no policy on "to" could constrain it
+		return nil, Empty, fmt.Errorf("only call operations (0) can be signed")
+	}
+
+	// The zero address is meaningful for the refund fields, so only their format is checked
+	gasToken, err := util.ParseAddress(data.Get("gas_token").(string), config.RequireChecksum)
+	if err != nil {
+		return nil, Empty, fmt.Errorf("invalid gas_token: %v", err)
+	}
+	refundReceiver, err := util.ParseAddress(data.Get("refund_receiver").(string), config.RequireChecksum)
+	if err != nil {
+		return nil, Empty, fmt.Errorf("invalid refund_receiver: %v", err)
+	}
+
+	safeTx := &SafeTx{
+		To:             *to,
+		Operation:      SafeOperationCall,
+		GasToken:       gasToken,
+		RefundReceiver: refundReceiver,
+	}
+	safeTx.Value, err = ParseAmount(data.Get("value").(string))
+	if err != nil {
+		return nil, Empty, fmt.Errorf("invalid value: %v", err)
+	}
+	for field, value := range map[string]**big.Int{
+		"safe_tx_gas":      &safeTx.SafeTxGas,
+		"base_gas":         &safeTx.BaseGas,
+		"refund_gas_price": &safeTx.GasPrice,
+	} {
+		*value = util.ValidNumber(data.Get(field).(string))
+		if *value == nil {
+			return nil, Empty, fmt.Errorf("invalid %s", field)
+		}
+	}
+	safeTx.Data, err = decodeHex(data.Get("data").(string))
+	if err != nil {
+		return nil, Empty, fmt.Errorf("invalid data: %v", err)
+	}
+	if nonce, ok := data.GetOk("nonce"); ok {
+		safeTx.Nonce = util.ValidNumber(nonce.(string))
+		if safeTx.Nonce == nil {
+			return nil, Empty, fmt.Errorf("invalid nonce")
+		}
+	} else {
+		safeTx.Nonce, err = safeSession.Nonce()
+		if err != nil {
+			return nil, Empty, err
+		}
+	}
+
+	err = config.ValidAddress(&safeTx.To)
+	if err != nil {
+		return nil, Empty, err
+	}
+	err = accountJSON.ValidAddress(&safeTx.To)
+	if err != nil {
+		return nil, Empty, err
+	}
+	// A refund moves funds out of the Safe too
+	if safeTx.GasPrice.Sign() > 0 && safeTx.RefundReceiver.Hex() != util.ZeroAddress {
+		err = config.ValidAddress(&safeTx.RefundReceiver)
+		if err != nil {
+			return nil, Empty, err
+		}
+		err = accountJSON.ValidAddress(&safeTx.RefundReceiver)
+		if err != nil {
+			return nil, Empty, err
+		}
+	}
+	return safeTx, toName, nil
+}
+
+// safeTransactionHash computes the SafeTx hash and confirms the Safe agrees with it before anything is signed
+func safeTransactionHash(config *ConfigJSON, safeSession *safe.GnosisSafeCallerSession, safeAddress *common.Address, safeTx *SafeTx) (common.Hash, error) {
+	chainID := util.ValidNumber(config.ChainID)
+	if chainID == nil {
+		return common.Hash{}, fmt.Errorf("invalid chain ID")
+	}
+	version, err := safeSession.VERSION()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	safeTxHash := safeTx.Hash(DomainSeparator(*safeAddress, chainID, version))
+
+	onChainHash, err := safeSession.GetTransactionHash(safeTx.To, safeTx.Value, safeTx.Data, safeTx.Operation, safeTx.SafeTxGas, safeTx.BaseGas, safeTx.GasPrice, safeTx.GasToken, safeTx.RefundReceiver, safeTx.Nonce)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if common.Hash(onChainHash) != safeTxHash {
+		return common.Hash{}, fmt.Errorf("the Safe computes %s for this transaction, not %s - is chain_id correct?", common.Hash(onChainHash).Hex(), safeTxHash.Hex())
+	}
+	return safeTxHash, nil
+}
+
+// safeSigner returns the owner that produced a Safe signature
+func safeSigner(safeTxHash common.Hash, signature []byte) (*common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("a signature must be %d bytes", crypto.SignatureLength)
+	}
+	v := signature[crypto.RecoveryIDOffset]
+	var hash []byte
+	switch {
+	case v == 1:
+		// The owner approved the hash on-chain; r holds the owner's address
+		owner := common.BytesToAddress(signature[:32])
+		return &owner, nil
+	case v == 27 || v == 28:
+		hash = safeTxHash.Bytes()
+	case v == 31 || v == 32:
+		// Signed with eth_sign, so the hash was prefixed and v was bumped by 4
+		hash = accounts.TextHash(safeTxHash.Bytes())
+		v -= 4
+	default:
+		return nil, fmt.Errorf("unsupported signature type v=%d", v)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	sig[crypto.RecoveryIDOffset] = v - 27
+	publicKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, err
+	}
+	owner := crypto.PubkeyToAddress(*publicKey)
+	return &owner, nil
+}
+
+func safeCallerSession(client *ethclient.Client, config *ConfigJSON, data *framework.FieldData) (*safe.GnosisSafeCallerSession, *common.Address, error) {
+	safeAddress, err := config.addressField(data, "safe")
+	if err != nil {
+		return nil, nil, err
+	}
+	instance, err := safe.NewGnosisSafeCaller(safeAddress, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &safe.GnosisSafeCallerSession{
+		Contract: instance,
+		CallOpts: bind.CallOpts{},
+	}, &safeAddress, nil
+}
+
+func safeTxResponse(safeAddress *common.Address, safeTx *SafeTx) *logical.Response {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"safe":             safeAddress.Hex(),
+			"to":               safeTx.To.Hex(),
+			"value":            safeTx.Value.String(),
+			"data":             hexutil.Encode(safeTx.Data),
+			"operation":        safeTx.Operation,
+			"safe_tx_gas":      safeTx.SafeTxGas.String(),
+			"base_gas":         safeTx.BaseGas.String(),
+			"refund_gas_price": safeTx.GasPrice.String(),
+			"gas_token":        safeTx.GasToken.Hex(),
+			"refund_receiver":  safeTx.RefundReceiver.Hex(),
+			"nonce":            safeTx.Nonce.String(),
+		},
+	}
+}
+
+// decodeHex decodes hex with or without the 0x prefix; empty input is empty bytes
+func decodeHex(input string) ([]byte, error) {
+	if input == Empty {
+		return []byte{}, nil
+	}
+	if !strings.HasPrefix(input, "0x") {
+		input = "0x" + input
+	}
+	return hexutil.Decode(input)
+}
+
+// abiUint256 returns the 32 byte ABI encoding of a uint256
+func abiUint256(value *big.Int) []byte {
+	return math.PaddedBigBytes(math.U256(new(big.Int).Set(value)), 32)
+}
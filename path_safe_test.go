@@ -0,0 +1,121 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core"
+)
+
+func TestSafeTypeHashes(t *testing.T) {
+	// SAFE_TX_TYPEHASH and DOMAIN_SEPARATOR_TYPEHASH as published in the Safe contracts
+	tests := []struct {
+		name string
+		got  common.Hash
+		want string
+	}{
+		{name: "SafeTx", got: safeTxTypeHash, want: "0xbb8310d486368db6bd6f849402fdd73ad53d316b5a4b2644ad6efe0f941286d8"},
+		{name: "domain 1.3.0", got: safeDomainTypeHash, want: "0x47e79534a245952e8b16893a336b85a3d9ea9fa8c573f3d803afb92a79469218"},
+		{name: "domain before 1.3.0", got: safeLegacyDomainTypeHash, want: "0x035aff83d86937d35b32e04f0ddc6ff469290eef2f1b692d8a815c89404d4749"},
+	}
+	for _, test := range tests {
+		if test.got.Hex() != test.want {
+			t.Errorf("%s type hash = %s, want %s", test.name, test.got.Hex(), test.want)
+		}
+	}
+}
+
+func TestSafeTxHash(t *testing.T) {
+	safe := common.HexToAddress("0x1c8b9B78e3085866521FE206fa4c1a67F49f153A")
+	chainID := big.NewInt(5)
+	tx := &SafeTx{
+		To:             common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		Value:          big.NewInt(1000000000000000000),
+		Data:           common.FromHex("0xa9059cbb000000000000000000000000fb6916095ca1df60bb79ce92ce3ea74c37c5d3590000000000000000000000000000000000000000000000000000000000000001"),
+		Operation:      1,
+		SafeTxGas:      big.NewInt(50000),
+		BaseGas:        big.NewInt(21000),
+		GasPrice:       big.NewInt(1000000000),
+		GasToken:       common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7"),
+		RefundReceiver: common.HexToAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"),
+		Nonce:          big.NewInt(42),
+	}
+
+	// The hash is checked against go-ethereum's independent EIP-712 encoder, which
+	// is how Safe{Wallet} and eth_signTypedData hash the same transaction
+	typedData := core.TypedData{
+		Types: core.Types{
+			"EIP712Domain": {
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain: core.TypedDataDomain{
+			ChainId:           math.NewHexOrDecimal256(chainID.Int64()),
+			VerifyingContract: safe.Hex(),
+		},
+		Message: core.TypedDataMessage{
+			"to":             tx.To.Hex(),
+			"value":          tx.Value.String(),
+			"data":           tx.Data,
+			"operation":      "1",
+			"safeTxGas":      tx.SafeTxGas.String(),
+			"baseGas":        tx.BaseGas.String(),
+			"gasPrice":       tx.GasPrice.String(),
+			"gasToken":       tx.GasToken.Hex(),
+			"refundReceiver": tx.RefundReceiver.Hex(),
+			"nonce":          tx.Nonce.String(),
+		},
+	}
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := DomainSeparator(safe, chainID, "1.3.0"); got != common.BytesToHash(domainSeparator) {
+		t.Fatalf("DomainSeparator = %s, want %s", got.Hex(), domainSeparator)
+	}
+	structHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator, structHash)
+	if got := tx.Hash(common.BytesToHash(domainSeparator)); got != want {
+		t.Fatalf("SafeTx hash = %s, want %s", got.Hex(), want.Hex())
+	}
+
+	// Safes before 1.3.0 don't bind the chain ID
+	legacy := crypto.Keccak256Hash(safeLegacyDomainTypeHash.Bytes(), common.LeftPadBytes(safe.Bytes(), 32))
+	if got := DomainSeparator(safe, chainID, "1.1.1"); got != legacy {
+		t.Fatalf("legacy DomainSeparator = %s, want %s", got.Hex(), legacy.Hex())
+	}
+}
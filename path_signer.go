@@ -0,0 +1,486 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	bip44 "github.com/immutability-io/go-ethereum-hdwallet"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+// SignerTransactionJSON is a transaction as web3 tooling sends it to an external signer
+type SignerTransactionJSON struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                *hexutil.Uint64 `json:"nonce"`
+	Data                 *hexutil.Bytes  `json:"data"`
+	Input                *hexutil.Bytes  `json:"input"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// signerAccount is a mount account that a signer request refers to by address
+type signerAccount struct {
+	name        string
+	accountJSON *AccountJSON
+	wallet      *bip44.Wallet
+	account     *accounts.Account
+}
+
+func signerPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      QualifiedPath("signer"),
+			HelpSynopsis: "An external signer for web3 tooling.",
+			HelpDescription: `
+
+Speaks the JSON-RPC methods that Foundry, Hardhat and ethers use with an external
+signer: eth_accounts, eth_chainId, eth_sign, eth_signTypedData_v4,
+eth_signTransaction and eth_sendTransaction. Accounts are referred to by address
+and are subject to the same inclusions, exclusions, restrictions and fee policy as
+sign-tx. Transactions are signed as legacy EIP-155 transactions; contract creation
+is refused, as it has no recipient to check, so use the deploy path.
+
+The spender of EIP-2612 and Permit2 permits, the recipient of EIP-3009 transfers
+and the to address of Safe transactions are held to the inclusions and exclusions.
+Safe delegatecalls are refused, as they are by the safe execute path, and permits
+and transfers must name the chain in their domain. eth_sign and any other typed
+data can authorize anything, so they are refused unless the account sets
+allow_message_signing.
+
+Any other method is forwarded to the node if it is in the mount's rpc_allowlist.
+
+The vault-signer command serves this path as a plain JSON-RPC endpoint on a local
+address, to requests that carry its bearer token.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"network": networkField(),
+				"method": {
+					Type:        framework.TypeString,
+					Description: "The JSON-RPC method, such as eth_sendTransaction.",
+				},
+				"params": {
+					Type:        framework.TypeSlice,
+					Description: "The parameters of the call.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathSigner,
+				logical.UpdateOperation: b.pathSigner,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathSigner(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	method := data.Get("method").(string)
+	params := data.Get("params").([]interface{})
+
+	var result interface{}
+	switch method {
+	case "eth_accounts":
+		result, err = b.signerAccounts(ctx, req, config)
+	case "eth_chainId":
+		chainID := util.ValidNumber(config.ChainID)
+		if chainID == nil {
+			return nil, fmt.Errorf("invalid chain ID")
+		}
+		result = hexutil.EncodeBig(chainID)
+	case "eth_sign":
+		result, err = b.signerSign(ctx, req, config, params)
+	case "eth_signTypedData_v4":
+		result, err = b.signerSignTypedData(ctx, req, config, params)
+	case "eth_signTransaction", "eth_sendTransaction":
+		result, err = b.signerTransaction(ctx, req, config, params, method == "eth_sendTransaction")
+	default:
+		result, err = b.forwardRPC(ctx, config, method, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"method": method,
+			"result": result,
+		},
+	}, nil
+}
+
+// signerAccounts lists the addresses of the accounts this request may use
+func (b *PluginBackend) signerAccounts(ctx context.Context, req *logical.Request, config *ConfigJSON) ([]string, error) {
+	names, err := req.Storage.List(ctx, QualifiedPath("accounts/"))
+	if err != nil {
+		return nil, err
+	}
+	addresses := []string{}
+	for _, name := range names {
+		accountJSON, err := b.accountFor(ctx, req, config, name)
+		if err != nil {
+			// Accounts restricted to other sources or chains aren't offered
+			continue
+		}
+		_, account, err := getWalletAndAccount(*accountJSON)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, account.Address.Hex())
+	}
+	return addresses, nil
+}
+
+// signerAccountFor finds the account with an address, subject to its restrictions
+func (b *PluginBackend) signerAccountFor(ctx context.Context, req *logical.Request, config *ConfigJSON, address common.Address) (*signerAccount, error) {
+	names, err := req.Storage.List(ctx, QualifiedPath("accounts/"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		accountJSON, err := readAccount(ctx, req, name)
+		if err != nil {
+			return nil, err
+		}
+		if accountJSON == nil {
+			continue
+		}
+		wallet, account, err := getWalletAndAccount(*accountJSON)
+		if err != nil {
+			return nil, err
+		}
+		if account.Address != address {
+			continue
+		}
+		accountJSON, err = b.accountFor(ctx, req, config, name)
+		if err != nil {
+			return nil, err
+		}
+		return &signerAccount{name: name, accountJSON: accountJSON, wallet: wallet, account: account}, nil
+	}
+	return nil, fmt.Errorf("%s is not an account of this mount", address.Hex())
+}
+
+// signerAddress reads an address parameter
+func signerAddress(params []interface{}, index int) (common.Address, error) {
+	if len(params) <= index {
+		return common.Address{}, fmt.Errorf("missing address parameter")
+	}
+	raw, ok := params[index].(string)
+	if !ok {
+		return common.Address{}, fmt.Errorf("the address parameter must be a string")
+	}
+	return util.ParseAddress(raw, false)
+}
+
+// validRecipient returns an error if the mount or the account may not send to or
+// grant to an address
+func (signer *signerAccount) validRecipient(config *ConfigJSON, address *common.Address) error {
+	if err := config.notReserved(address); err != nil {
+		return err
+	}
+	if err := config.ValidAddress(address); err != nil {
+		return err
+	}
+	return signer.accountJSON.ValidAddress(address)
+}
+
+// allowsMessageSigning returns an error unless the account signs what the signer can't check
+func (signer *signerAccount) allowsMessageSigning(what string) error {
+	if !signer.accountJSON.AllowMessageSigning {
+		return fmt.Errorf("account %s doesn't allow %s - set allow_message_signing to sign it", signer.name, what)
+	}
+	return nil
+}
+
+// signHash signs a hash with V as 27 or 28, as web3 tooling expects
+func (signer *signerAccount) signHash(hash []byte) (string, error) {
+	signature, err := signer.wallet.SignHash(*signer.account, hash)
+	if err != nil {
+		return Empty, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(signature), nil
+}
+
+// signerSign signs a message with the Ethereum signed message prefix, as eth_sign
+// takes an address and hex encoded data
+func (b *PluginBackend) signerSign(ctx context.Context, req *logical.Request, config *ConfigJSON, params []interface{}) (string, error) {
+	address, err := signerAddress(params, 0)
+	if err != nil {
+		return Empty, err
+	}
+	if len(params) < 2 {
+		return Empty, fmt.Errorf("missing data parameter")
+	}
+	raw, ok := params[1].(string)
+	if !ok {
+		return Empty, fmt.Errorf("the data parameter must be a hex string")
+	}
+	message, err := hexutil.Decode(raw)
+	if err != nil {
+		return Empty, fmt.Errorf("invalid data: %v", err)
+	}
+	signer, err := b.signerAccountFor(ctx, req, config, address)
+	if err != nil {
+		return Empty, err
+	}
+	// A raw message can authorize anything, so it isn't signed unless the account opts in
+	if err := signer.allowsMessageSigning("eth_sign"); err != nil {
+		return Empty, err
+	}
+	hash, _ := accounts.TextAndHash(message)
+	return signer.signHash(hash)
+}
+
+// typedDataRecipients names the field holding the address that typed data of a known
+// kind grants to or sends to
+var typedDataRecipients = map[string]string{
+	// EIP-2612 and DAI permits
+	"Permit": "spender",
+	// Permit2 allowances and signature transfers
+	"PermitSingle":            "spender",
+	"PermitBatch":             "spender",
+	"PermitTransferFrom":      "spender",
+	"PermitBatchTransferFrom": "spender",
+	// EIP-3009 transfers
+	"TransferWithAuthorization": "to",
+	"ReceiveWithAuthorization":  "to",
+	// Safe transactions
+	"SafeTx": "to",
+}
+
+// checkTypedData holds typed data of a known kind to the inclusions and exclusions.
+// Typed data of any other kind is refused unless the account allows message signing.
+func (signer *signerAccount) checkTypedData(config *ConfigJSON, typedData *core.TypedData) error {
+	field, ok := typedDataRecipients[typedData.PrimaryType]
+	if !ok {
+		return signer.allowsMessageSigning(fmt.Sprintf("%s typed data", typedData.PrimaryType))
+	}
+	// A permit or transfer without a chain ID could be replayed on every chain. Safes
+	// before 1.3.0 leave the chain out of SafeTx domains, and their address binds them.
+	if typedData.PrimaryType != "SafeTx" && typedData.Domain.ChainId == nil {
+		return fmt.Errorf("the %s typed data has no domain chainId", typedData.PrimaryType)
+	}
+	if typedData.PrimaryType == "SafeTx" {
+		// A delegatecall runs arbitrary code as the Safe, so no policy on "to" could
+		// constrain it - as with the safe execute path, only calls are signed
+		operation, _ := typedData.Message["operation"].(string)
+		if number := util.ValidNumber(operation); operation == Empty || number == nil || number.Cmp(big.NewInt(int64(SafeOperationCall))) != 0 {
+			return fmt.Errorf("only SafeTx call operations (0) can be signed")
+		}
+	}
+	raw, ok := typedData.Message[field].(string)
+	if !ok {
+		return fmt.Errorf("the %s typed data has no %s address", typedData.PrimaryType, field)
+	}
+	address, err := util.ParseAddress(raw, false)
+	if err != nil {
+		return fmt.Errorf("invalid %s address: %v", field, err)
+	}
+	return signer.validRecipient(config, &address)
+}
+
+// signerSignTypedData signs EIP-712 typed data, given as an object or as JSON. Typed
+// data for another chain is refused.
+func (b *PluginBackend) signerSignTypedData(ctx context.Context, req *logical.Request, config *ConfigJSON, params []interface{}) (string, error) {
+	address, err := signerAddress(params, 0)
+	if err != nil {
+		return Empty, err
+	}
+	if len(params) < 2 {
+		return Empty, fmt.Errorf("missing typed data parameter")
+	}
+	typedDataRaw := params[1]
+	if encoded, ok := typedDataRaw.(string); ok {
+		decoder := json.NewDecoder(strings.NewReader(encoded))
+		decoder.UseNumber()
+		if err := decoder.Decode(&typedDataRaw); err != nil {
+			return Empty, fmt.Errorf("invalid typed data: %v", err)
+		}
+	}
+	// Numbers are passed on as strings so that large integers aren't rounded, and
+	// because the domain's chainId can only be read from a string
+	raw, err := json.Marshal(numbersAsStrings(typedDataRaw))
+	if err != nil {
+		return Empty, err
+	}
+	var typedData core.TypedData
+	if err := json.Unmarshal(raw, &typedData); err != nil {
+		return Empty, fmt.Errorf("invalid typed data: %v", err)
+	}
+	if typedData.Domain.ChainId != nil {
+		chainID := util.ValidNumber(config.ChainID)
+		if chainID == nil || (*big.Int)(typedData.Domain.ChainId).Cmp(chainID) != 0 {
+			return Empty, fmt.Errorf("the typed data is for chain %s, not %s", (*big.Int)(typedData.Domain.ChainId), config.ChainID)
+		}
+	}
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return Empty, fmt.Errorf("invalid typed data domain: %v", err)
+	}
+	typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return Empty, fmt.Errorf("invalid typed data message: %v", err)
+	}
+	signer, err := b.signerAccountFor(ctx, req, config, address)
+	if err != nil {
+		return Empty, err
+	}
+	if err := signer.checkTypedData(config, &typedData); err != nil {
+		return Empty, err
+	}
+	hash := crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, typedDataHash)
+	return signer.signHash(hash)
+}
+
+// numbersAsStrings replaces the numbers in decoded JSON with their decimal strings
+func numbersAsStrings(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = numbersAsStrings(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = numbersAsStrings(item)
+		}
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return value
+}
+
+// signerTransaction signs a transaction, and sends it if send is set. It is held to the
+// same checks as sign-tx.
+func (b *PluginBackend) signerTransaction(ctx context.Context, req *logical.Request, config *ConfigJSON, params []interface{}, send bool) (interface{}, error) {
+	if len(params) < 1 {
+		return nil, fmt.Errorf("missing transaction parameter")
+	}
+	raw, err := json.Marshal(params[0])
+	if err != nil {
+		return nil, err
+	}
+	var args SignerTransactionJSON
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %v", err)
+	}
+	chainID := util.ValidNumber(config.ChainID)
+	if chainID == nil {
+		return nil, fmt.Errorf("invalid chain ID")
+	}
+	if args.ChainID != nil && args.ChainID.ToInt().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("the transaction is for chain %s, not %s", args.ChainID.ToInt(), config.ChainID)
+	}
+	// A contract creation has no recipient to hold to the inclusions and exclusions
+	if args.To == nil {
+		return nil, fmt.Errorf("the signer doesn't create contracts - use the deploy path")
+	}
+	signer, err := b.signerAccountFor(ctx, req, config, args.From)
+	if err != nil {
+		return nil, err
+	}
+	if err := signer.validRecipient(config, args.To); err != nil {
+		return nil, err
+	}
+
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	msg := ethereum.CallMsg{From: args.From, To: args.To, Value: big.NewInt(0)}
+	if args.Value != nil {
+		msg.Value = args.Value.ToInt()
+	}
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	gasLimit, err := b.policyGasLimit(ctx, client, config, signer.accountJSON, msg)
+	if err != nil {
+		return nil, err
+	}
+	// Legacy transactions pay their whole gas price, so an EIP-1559 fee cap only
+	// limits the price the fee policy chooses
+	var gasPrice *big.Int
+	if args.GasPrice != nil {
+		gasPrice = args.GasPrice.ToInt()
+	}
+	gasPrice, err = b.policyGasPrice(ctx, config, signer.accountJSON, gasPrice)
+	if err != nil {
+		return nil, err
+	}
+	if args.GasPrice == nil && args.MaxFeePerGas != nil && gasPrice.Cmp(args.MaxFeePerGas.ToInt()) > 0 {
+		return nil, fmt.Errorf("the gas price of %s exceeds the transaction's maxFeePerGas of %s", gasPrice, args.MaxFeePerGas.ToInt())
+	}
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	} else {
+		nonce, err = client.PendingNonceAt(ctx, args.From)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx := types.NewTransaction(nonce, *args.To, msg.Value, gasLimit, gasPrice, msg.Data)
+	signedTx, err := signer.wallet.SignTxEIP155(*signer.account, tx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if send {
+		if err := client.SendTransaction(ctx, signedTx); err != nil {
+			return nil, err
+		}
+		if _, err := recordTransaction(ctx, req, config, signer.name, args.From, signedTx, "signer"); err != nil {
+			return nil, err
+		}
+		return signedTx.Hash().Hex(), nil
+	}
+	var signedTxBuff bytes.Buffer
+	if err := signedTx.EncodeRLP(&signedTxBuff); err != nil {
+		return nil, err
+	}
+	return hexutil.Encode(signedTxBuff.Bytes()), nil
+}
@@ -0,0 +1,75 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core"
+)
+
+func TestCheckTypedData(t *testing.T) {
+	const (
+		included = "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"
+		excluded = "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"
+		other    = "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb"
+	)
+	config := &ConfigJSON{Exclusions: []string{excluded}}
+	tests := []struct {
+		name        string
+		primaryType string
+		message     core.TypedDataMessage
+		inclusions  []string
+		allow       bool
+		noChainID   bool
+		wantErr     bool
+	}{
+		{name: "permit", primaryType: "Permit", message: core.TypedDataMessage{"spender": included}},
+		{name: "permit to an excluded spender", primaryType: "Permit", message: core.TypedDataMessage{"spender": excluded}, wantErr: true},
+		{name: "permit to a spender not included", primaryType: "Permit", message: core.TypedDataMessage{"spender": other}, inclusions: []string{included}, wantErr: true},
+		{name: "permit to the zero address", primaryType: "Permit", message: core.TypedDataMessage{"spender": "0x0000000000000000000000000000000000000000"}, wantErr: true},
+		{name: "permit without a spender", primaryType: "Permit", message: core.TypedDataMessage{"owner": included}, wantErr: true},
+		{name: "permit without a chain ID", primaryType: "Permit", message: core.TypedDataMessage{"spender": included}, noChainID: true, wantErr: true},
+		{name: "Permit2 batch", primaryType: "PermitBatch", message: core.TypedDataMessage{"spender": excluded}, wantErr: true},
+		{name: "EIP-3009 transfer", primaryType: "TransferWithAuthorization", message: core.TypedDataMessage{"to": excluded}, wantErr: true},
+		{name: "Safe transaction", primaryType: "SafeTx", message: core.TypedDataMessage{"to": included, "operation": "0"}, inclusions: []string{included}},
+		{name: "Safe transaction to an excluded address", primaryType: "SafeTx", message: core.TypedDataMessage{"to": excluded, "operation": "0"}, allow: true, wantErr: true},
+		{name: "Safe transaction before 1.3.0", primaryType: "SafeTx", message: core.TypedDataMessage{"to": included, "operation": "0"}, noChainID: true},
+		{name: "Safe delegatecall", primaryType: "SafeTx", message: core.TypedDataMessage{"to": included, "operation": "1"}, allow: true, wantErr: true},
+		{name: "Safe transaction without an operation", primaryType: "SafeTx", message: core.TypedDataMessage{"to": included}, wantErr: true},
+		{name: "unknown typed data", primaryType: "Mail", message: core.TypedDataMessage{"contents": "hello"}, wantErr: true},
+		{name: "unknown typed data allowed", primaryType: "Mail", message: core.TypedDataMessage{"contents": "hello"}, allow: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signer := &signerAccount{
+				name:        "test",
+				accountJSON: &AccountJSON{Inclusions: test.inclusions, AllowMessageSigning: test.allow},
+			}
+			typedData := &core.TypedData{PrimaryType: test.primaryType, Message: test.message}
+			if !test.noChainID {
+				typedData.Domain.ChainId = math.NewHexOrDecimal256(1)
+			}
+			err := signer.checkTypedData(config, typedData)
+			if test.wantErr && err == nil {
+				t.Fatal("checkTypedData succeeded, want an error")
+			}
+			if !test.wantErr && err != nil {
+				t.Fatalf("checkTypedData failed: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+func statusPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      QualifiedPath("status"),
+			HelpSynopsis: "Report the health of the node and chain.",
+			HelpDescription: `
+
+Asks the node requests are sent to for its chain ID, latest block, sync status, peer
+count, client version and base fee, and measures the round trip. The node is healthy
+if it is on the configured chain and isn't syncing, so monitoring can alert before
+signing requests start failing.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"network": networkField(),
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: b.pathStatus,
+			},
+		},
+		{
+			Pattern:      QualifiedPath("status/endpoints"),
+			HelpSynopsis: "Report the health of a network's RPC endpoints.",
+			HelpDescription: `
+
+Checks every RPC endpoint of the network: whether it answers, whether it is on the
+configured chain, how far it lags behind the highest block among the endpoints and
+how long it took to respond. Requests use the first healthy endpoint in the
+configured order.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"network": networkField(),
+			},
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: b.pathEndpointStatus,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathEndpointStatus(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []map[string]interface{}
+	active, fallback := Empty, Empty
+	for _, endpoint := range b.endpointHealth(ctx, config, true) {
+		if active == Empty && endpoint.Healthy && !endpoint.Degraded {
+			active = endpoint.URL
+		}
+		if fallback == Empty && endpoint.Healthy {
+			fallback = endpoint.URL
+		}
+		endpoints = append(endpoints, map[string]interface{}{
+			"url":          endpoint.URL,
+			"healthy":      endpoint.Healthy,
+			"degraded":     endpoint.Degraded,
+			"chain_id":     endpoint.ChainID,
+			"block_number": endpoint.BlockNumber,
+			"block_lag":    endpoint.BlockLag,
+			"latency_ms":   endpoint.Latency.Milliseconds(),
+			"error":        endpoint.Error,
+			"checked_at":   endpoint.CheckedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	if active == Empty {
+		active = fallback
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"network":   config.Network,
+			"chain_id":  config.ChainID,
+			"active":    active,
+			"endpoints": endpoints,
+		},
+	}, nil
+}
+
+func (b *PluginBackend) pathStatus(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	response := &logical.Response{
+		Data: map[string]interface{}{
+			"network":  config.Network,
+			"chain_id": config.ChainID,
+			"healthy":  false,
+		},
+	}
+	// Ask the endpoint requests are sent to, or the first one if none is usable, so
+	// that a node on the wrong chain is reported rather than skipped
+	url, fallback := Empty, Empty
+	for _, endpoint := range b.endpointHealth(ctx, config, false) {
+		if url == Empty && endpoint.Healthy && !endpoint.Degraded {
+			url = endpoint.URL
+		}
+		if fallback == Empty && endpoint.Healthy {
+			fallback = endpoint.URL
+		}
+	}
+	if url == Empty {
+		url = fallback
+	}
+	if url == Empty {
+		url = config.rpcURLs()[0]
+	}
+	response.Data["rpc_url"] = url
+	client, err := b.dial(url, config.rpcAuth())
+	if err != nil {
+		response.Data["error"] = err.Error()
+		return response, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	var nodeChainID hexutil.Big
+	if err := client.CallContext(ctx, &nodeChainID, "eth_chainId"); err != nil {
+		response.Data["error"] = err.Error()
+		return response, nil
+	}
+	response.Data["latency_ms"] = time.Since(start).Milliseconds()
+	response.Data["node_chain_id"] = nodeChainID.ToInt().String()
+	expected := util.ValidNumber(config.ChainID)
+	chainMatches := expected != nil && expected.Cmp(nodeChainID.ToInt()) == 0
+	response.Data["chain_id_matches"] = chainMatches
+
+	var block struct {
+		Number        *hexutil.Big   `json:"number"`
+		Timestamp     hexutil.Uint64 `json:"timestamp"`
+		BaseFeePerGas *hexutil.Big   `json:"baseFeePerGas"`
+	}
+	if err := client.CallContext(ctx, &block, "eth_getBlockByNumber", "latest", false); err != nil {
+		response.Data["error"] = err.Error()
+		return response, nil
+	}
+	if block.Number == nil {
+		response.Data["error"] = "the node returned no latest block"
+		return response, nil
+	}
+	blockTime := time.Unix(int64(block.Timestamp), 0)
+	response.Data["block_number"] = block.Number.ToInt().String()
+	response.Data["block_timestamp"] = blockTime.UTC().Format(time.RFC3339)
+	response.Data["block_age_seconds"] = int64(time.Since(blockTime).Seconds())
+	if block.BaseFeePerGas != nil {
+		response.Data["base_fee"] = block.BaseFeePerGas.ToInt().String()
+	}
+
+	// eth_syncing returns false, or the progress of the sync
+	var syncing json.RawMessage
+	if err := client.CallContext(ctx, &syncing, "eth_syncing"); err != nil {
+		response.Data["error"] = err.Error()
+		return response, nil
+	}
+	var progress struct {
+		CurrentBlock hexutil.Uint64 `json:"currentBlock"`
+		HighestBlock hexutil.Uint64 `json:"highestBlock"`
+	}
+	isSyncing := string(syncing) != "false" && json.Unmarshal(syncing, &progress) == nil
+	response.Data["syncing"] = isSyncing
+	if isSyncing {
+		response.Data["sync_current_block"] = uint64(progress.CurrentBlock)
+		response.Data["sync_highest_block"] = uint64(progress.HighestBlock)
+	}
+
+	// Hosted providers often don't serve these, which doesn't make the node unhealthy
+	var peerCount hexutil.Uint64
+	if err := client.CallContext(ctx, &peerCount, "net_peerCount"); err != nil {
+		response.AddWarning(fmt.Sprintf("net_peerCount failed: %v", err))
+	} else {
+		response.Data["peer_count"] = uint64(peerCount)
+	}
+	var clientVersion string
+	if err := client.CallContext(ctx, &clientVersion, "web3_clientVersion"); err != nil {
+		response.AddWarning(fmt.Sprintf("web3_clientVersion failed: %v", err))
+	} else {
+		response.Data["client_version"] = clientVersion
+	}
+
+	response.Data["healthy"] = chainMatches && !isSyncing
+	return response, nil
+}
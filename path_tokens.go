@@ -0,0 +1,240 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/contracts/erc20"
+	"github.com/immutability-io/vault-ethereum/util"
+	"github.com/shopspring/decimal"
+)
+
+// TokenJSON is an ERC-20 token registered for conversions
+type TokenJSON struct {
+	Address      string            `json:"address"`
+	ChainID      string            `json:"chain_id"`
+	Decimals     uint8             `json:"decimals"`
+	CoinGeckoID  string            `json:"coingecko_id"`
+	Feeds        map[string]string `json:"feeds"`
+	StaticPrices map[string]string `json:"static_prices"`
+}
+
+// asset returns the token as an asset to be priced
+func (token *TokenJSON) asset(symbol string) *Asset {
+	return &Asset{
+		Name:         symbol,
+		CoinGeckoID:  token.CoinGeckoID,
+		Feeds:        token.Feeds,
+		StaticPrices: token.StaticPrices,
+	}
+}
+
+func tokenPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: QualifiedPath("config/tokens/?"),
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ListOperation: b.pathTokensList,
+			},
+			HelpSynopsis: "List the tokens registered on this mount",
+			HelpDescription: `
+			All the registered token symbols will be listed.
+			`,
+		},
+		{
+			Pattern:      QualifiedPath("config/tokens/" + framework.GenericNameRegex("symbol")),
+			HelpSynopsis: "Register an ERC-20 token for conversions.",
+			HelpDescription: `
+
+Registers an ERC-20 token by its contract so that convert accepts its symbol as a
+unit. The token's decimals are read from the contract, and its price comes from the
+mount's price source using the token's CoinGecko ID, Chainlink price feeds or static
+prices.
+
+A token is registered on the chain of the selected network, and can only be
+converted on that chain.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"symbol": {Type: framework.TypeString},
+				"address": {
+					Type:        framework.TypeString,
+					Description: "The address of the token contract.",
+				},
+				"network": networkField(),
+				"coingecko_id": {
+					Type:        framework.TypeString,
+					Description: "The CoinGecko coin of the token, such as usd-coin.",
+				},
+				"price_feeds": {
+					Type:        framework.TypeKVPairs,
+					Description: "The Chainlink aggregator of the token for each currency.",
+				},
+				"static_prices": {
+					Type:        framework.TypeKVPairs,
+					Description: "The price of the token in each currency when the source is static.",
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation:   b.pathTokensRead,
+				logical.CreateOperation: b.pathTokensWrite,
+				logical.UpdateOperation: b.pathTokensWrite,
+				logical.DeleteOperation: b.pathTokensDelete,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathTokensList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	vals, err := req.Storage.List(ctx, QualifiedPath("tokens/"))
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(vals), nil
+}
+
+func (b *PluginBackend) pathTokensRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	token, err := readToken(ctx, req.Storage, data.Get("symbol").(string))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, nil
+	}
+	return tokenResponse(token), nil
+}
+
+func (b *PluginBackend) pathTokensWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	symbol := strings.ToLower(data.Get("symbol").(string))
+	if _, err := ValidUnit(symbol); err == nil || util.Contains(config.PriceOracle.currencies(), symbol) {
+		return nil, fmt.Errorf("%s is already a unit or a currency", symbol)
+	}
+	token, err := readToken(ctx, req.Storage, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		token = &TokenJSON{}
+	}
+	if address, ok := data.GetOk("address"); ok {
+		contractAddress, err := config.parseAddress(address.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid address: %v", err)
+		}
+		token.Address = contractAddress.Hex()
+	}
+	if token.Address == Empty {
+		return nil, fmt.Errorf("address is required")
+	}
+	if coinGeckoID, ok := data.GetOk("coingecko_id"); ok {
+		token.CoinGeckoID = coinGeckoID.(string)
+	}
+	if feeds, ok := data.GetOk("price_feeds"); ok {
+		token.Feeds = make(map[string]string)
+		for currency, feed := range feeds.(map[string]string) {
+			address, err := util.ParseAddress(feed, false)
+			if err != nil {
+				return nil, fmt.Errorf("invalid price feed for %s: %v", currency, err)
+			}
+			token.Feeds[strings.ToLower(currency)] = address.Hex()
+		}
+	}
+	if prices, ok := data.GetOk("static_prices"); ok {
+		token.StaticPrices = make(map[string]string)
+		for currency, price := range prices.(map[string]string) {
+			value, err := decimal.NewFromString(price)
+			if err != nil || !value.IsPositive() {
+				return nil, fmt.Errorf("invalid static price for %s: %s", currency, price)
+			}
+			token.StaticPrices[strings.ToLower(currency)] = value.String()
+		}
+	}
+
+	// The decimals are read from the contract, which also checks that it is a token
+	client, err := b.client(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := erc20.NewErc20Caller(common.HexToAddress(token.Address), client)
+	if err != nil {
+		return nil, err
+	}
+	token.Decimals, err = instance.Decimals(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the decimals of %s: %v", token.Address, err)
+	}
+	token.ChainID = config.ChainID
+
+	entry, err := logical.StorageEntryJSON(QualifiedPath("tokens/"+symbol), token)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+	b.resetPrices()
+	return tokenResponse(token), nil
+}
+
+func (b *PluginBackend) pathTokensDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	symbol := strings.ToLower(data.Get("symbol").(string))
+	if err := req.Storage.Delete(ctx, QualifiedPath("tokens/"+symbol)); err != nil {
+		return nil, err
+	}
+	b.resetPrices()
+	return nil, nil
+}
+
+func tokenResponse(token *TokenJSON) *logical.Response {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"address":       token.Address,
+			"chain_id":      token.ChainID,
+			"decimals":      token.Decimals,
+			"coingecko_id":  token.CoinGeckoID,
+			"price_feeds":   token.Feeds,
+			"static_prices": token.StaticPrices,
+		},
+	}
+}
+
+func readToken(ctx context.Context, s logical.Storage, symbol string) (*TokenJSON, error) {
+	path := QualifiedPath("tokens/" + strings.ToLower(symbol))
+	entry, err := s.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var token TokenJSON
+	if err := entry.DecodeJSON(&token); err != nil {
+		return nil, fmt.Errorf("failed to deserialize token at %s", path)
+	}
+	return &token, nil
+}
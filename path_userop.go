@@ -0,0 +1,441 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/immutability-io/vault-ethereum/util"
+)
+
+const (
+	userOperationContract string = "erc-4337"
+	// EntryPointV06 is the canonical v0.6 EntryPoint
+	EntryPointV06 string = "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"
+	// EntryPointV07 is the canonical v0.7 EntryPoint
+	EntryPointV07 string = "0x0000000071727De22E5E9d8BAf0edAc6f37da032"
+	// UserOperationV06 is the unpacked UserOperation struct of EntryPoint v0.6
+	UserOperationV06 string = "v0.6"
+	// UserOperationV07 is the PackedUserOperation struct of EntryPoint v0.7
+	UserOperationV07 string = "v0.7"
+)
+
+// UserOperation holds the fields of both UserOperation versions. For v0.7 the
+// packed init code and paymaster data are split into their parts so that the
+// operation can be sent to a bundler in the form it expects.
+type UserOperation struct {
+	Version                       string
+	Sender                        common.Address
+	Nonce                         *big.Int
+	InitCode                      []byte
+	CallData                      []byte
+	CallGasLimit                  *big.Int
+	VerificationGasLimit          *big.Int
+	PreVerificationGas            *big.Int
+	MaxFeePerGas                  *big.Int
+	MaxPriorityFeePerGas          *big.Int
+	PaymasterAndData              []byte
+	Factory                       *common.Address
+	FactoryData                   []byte
+	Paymaster                     *common.Address
+	PaymasterVerificationGasLimit *big.Int
+	PaymasterPostOpGasLimit       *big.Int
+	PaymasterData                 []byte
+	Signature                     []byte
+}
+
+// packUint128 packs two uint128 values into a bytes32, high half first
+func packUint128(high *big.Int, low *big.Int) []byte {
+	packed := make([]byte, 32)
+	copy(packed[:16], math.PaddedBigBytes(high, 16))
+	copy(packed[16:], math.PaddedBigBytes(low, 16))
+	return packed
+}
+
+// Hash returns the userOpHash the EntryPoint computes for this operation
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) common.Hash {
+	var packed []byte
+	if op.Version == UserOperationV06 {
+		packed = crypto.Keccak256(
+			common.LeftPadBytes(op.Sender.Bytes(), 32),
+			abiUint256(op.Nonce),
+			crypto.Keccak256(op.InitCode),
+			crypto.Keccak256(op.CallData),
+			abiUint256(op.CallGasLimit),
+			abiUint256(op.VerificationGasLimit),
+			abiUint256(op.PreVerificationGas),
+			abiUint256(op.MaxFeePerGas),
+			abiUint256(op.MaxPriorityFeePerGas),
+			crypto.Keccak256(op.PaymasterAndData),
+		)
+	} else {
+		packed = crypto.Keccak256(
+			common.LeftPadBytes(op.Sender.Bytes(), 32),
+			abiUint256(op.Nonce),
+			crypto.Keccak256(op.InitCode),
+			crypto.Keccak256(op.CallData),
+			packUint128(op.VerificationGasLimit, op.CallGasLimit),
+			abiUint256(op.PreVerificationGas),
+			packUint128(op.MaxPriorityFeePerGas, op.MaxFeePerGas),
+			crypto.Keccak256(op.PaymasterAndData),
+		)
+	}
+	return crypto.Keccak256Hash(packed, common.LeftPadBytes(entryPoint.Bytes(), 32), abiUint256(chainID))
+}
+
+// RPCFields returns the operation as eth_sendUserOperation expects it
+func (op *UserOperation) RPCFields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"sender":               op.Sender.Hex(),
+		"nonce":                hexutil.EncodeBig(op.Nonce),
+		"callData":             hexutil.Encode(op.CallData),
+		"callGasLimit":         hexutil.EncodeBig(op.CallGasLimit),
+		"verificationGasLimit": hexutil.EncodeBig(op.VerificationGasLimit),
+		"preVerificationGas":   hexutil.EncodeBig(op.PreVerificationGas),
+		"maxFeePerGas":         hexutil.EncodeBig(op.MaxFeePerGas),
+		"maxPriorityFeePerGas": hexutil.EncodeBig(op.MaxPriorityFeePerGas),
+		"signature":            hexutil.Encode(op.Signature),
+	}
+	if op.Version == UserOperationV06 {
+		fields["initCode"] = hexutil.Encode(op.InitCode)
+		fields["paymasterAndData"] = hexutil.Encode(op.PaymasterAndData)
+		return fields
+	}
+	if op.Factory != nil {
+		fields["factory"] = op.Factory.Hex()
+		fields["factoryData"] = hexutil.Encode(op.FactoryData)
+	}
+	if op.Paymaster != nil {
+		fields["paymaster"] = op.Paymaster.Hex()
+		fields["paymasterVerificationGasLimit"] = hexutil.EncodeBig(op.PaymasterVerificationGasLimit)
+		fields["paymasterPostOpGasLimit"] = hexutil.EncodeBig(op.PaymasterPostOpGasLimit)
+		fields["paymasterData"] = hexutil.Encode(op.PaymasterData)
+	}
+	return fields
+}
+
+func userOperationPaths(b *PluginBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern:      ContractPath(userOperationContract, "sign"),
+			HelpSynopsis: "Sign an ERC-4337 user operation as the owner of a smart contract account.",
+			HelpDescription: `
+
+Computes the userOpHash of a v0.6 UserOperation or a v0.7 PackedUserOperation for
+the given EntryPoint and the chain ID of the mount, and signs it with this account.
+The v0.7 init code and paymaster data can be given packed or as their parts. If
+submit is true, the signed operation is sent to the bundler configured on the
+mount with eth_sendUserOperation.
+
+`,
+			Fields: map[string]*framework.FieldSchema{
+				"name":    {Type: framework.TypeString},
+				"network": networkField(),
+				"version": {
+					Type:        framework.TypeString,
+					Description: "The UserOperation format: v0.6 or v0.7.",
+					Default:     UserOperationV07,
+				},
+				"entry_point": {
+					Type:        framework.TypeString,
+					Description: "The EntryPoint address - defaults to the canonical EntryPoint of the version.",
+				},
+				"sender": {
+					Type:        framework.TypeString,
+					Description: "The smart contract account sending the operation.",
+				},
+				"nonce": {
+					Type:        framework.TypeString,
+					Description: "The EntryPoint nonce of the sender.",
+				},
+				"init_code": {
+					Type:        framework.TypeString,
+					Description: "Hex encoded factory address and calldata, if the account is not deployed yet.",
+				},
+				"factory": {
+					Type:        framework.TypeString,
+					Description: "v0.7 only: the factory address, instead of init_code.",
+				},
+				"factory_data": {
+					Type:        framework.TypeString,
+					Description: "v0.7 only: hex encoded factory calldata, instead of init_code.",
+				},
+				"call_data": {
+					Type:        framework.TypeString,
+					Description: "Hex encoded calldata the account executes.",
+				},
+				"call_gas_limit": {
+					Type:        framework.TypeString,
+					Description: "Gas for the main execution call.",
+				},
+				"verification_gas_limit": {
+					Type:        framework.TypeString,
+					Description: "Gas for the verification step.",
+				},
+				"pre_verification_gas": {
+					Type:        framework.TypeString,
+					Description: "Gas paid to the bundler for overhead.",
+				},
+				"max_fee_per_gas": {
+					Type:        framework.TypeString,
+					Description: "Maximum fee per gas (in wei).",
+				},
+				"max_priority_fee_per_gas": {
+					Type:        framework.TypeString,
+					Description: "Maximum priority fee per gas (in wei).",
+				},
+				"paymaster_and_data": {
+					Type:        framework.TypeString,
+					Description: "Hex encoded paymaster address and data, if a paymaster pays for the operation.",
+				},
+				"paymaster": {
+					Type:        framework.TypeString,
+					Description: "v0.7 only: the paymaster address, instead of paymaster_and_data.",
+				},
+				"paymaster_verification_gas_limit": {
+					Type:        framework.TypeString,
+					Description: "v0.7 only: gas for the paymaster's validation.",
+				},
+				"paymaster_post_op_gas_limit": {
+					Type:        framework.TypeString,
+					Description: "v0.7 only: gas for the paymaster's postOp.",
+				},
+				"paymaster_data": {
+					Type:        framework.TypeString,
+					Description: "v0.7 only: hex encoded paymaster data.",
+				},
+				"eth_sign": {
+					Type:        framework.TypeBool,
+					Description: "Sign the userOpHash with the eth_sign prefix, as most smart contract accounts expect.",
+					Default:     true,
+				},
+				"submit": {
+					Type:        framework.TypeBool,
+					Description: "Send the signed operation to the mount's bundler.",
+					Default:     false,
+				},
+			},
+			ExistenceCheck: pathExistenceCheck,
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.CreateOperation: b.pathSignUserOperation,
+				logical.UpdateOperation: b.pathSignUserOperation,
+			},
+		},
+	}
+}
+
+func (b *PluginBackend) pathSignUserOperation(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	config, err := b.configuredNetwork(ctx, req, data)
+	if err != nil {
+		return nil, err
+	}
+	name := data.Get("name").(string)
+	accountJSON, err := b.accountFor(ctx, req, config, name)
+	if err != nil {
+		return nil, err
+	}
+	chainID := util.ValidNumber(config.ChainID)
+	if chainID == nil {
+		return nil, fmt.Errorf("invalid chain ID")
+	}
+
+	op, err := userOperation(config, data)
+	if err != nil {
+		return nil, err
+	}
+	entryPoint, err := userOperationEntryPoint(op.Version, data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = config.ValidAddress(&op.Sender)
+	if err != nil {
+		return nil, err
+	}
+	err = accountJSON.ValidAddress(&op.Sender)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, account, err := getWalletAndAccount(*accountJSON)
+	if err != nil {
+		return nil, err
+	}
+	userOpHash := op.Hash(*entryPoint, chainID)
+	hash := userOpHash.Bytes()
+	if data.Get("eth_sign").(bool) {
+		hash = accounts.TextHash(hash)
+	}
+	signature, err := wallet.SignHash(*account, hash)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	op.Signature = signature
+
+	response := &logical.Response{
+		Data: map[string]interface{}{
+			"version":        op.Version,
+			"entry_point":    entryPoint.Hex(),
+			"chain_id":       chainID.String(),
+			"user_op_hash":   userOpHash.Hex(),
+			"signature":      hexutil.Encode(signature),
+			"owner":          account.Address.Hex(),
+			"user_operation": op.RPCFields(),
+		},
+	}
+
+	if data.Get("submit").(bool) {
+		if config.BundlerURL == Empty {
+			return nil, fmt.Errorf("no bundler_url is configured for this network")
+		}
+		bundler, err := rpc.DialContext(ctx, config.BundlerURL)
+		if err != nil {
+			return nil, err
+		}
+		defer bundler.Close()
+		var submittedHash common.Hash
+		err = bundler.CallContext(ctx, &submittedHash, "eth_sendUserOperation", op.RPCFields(), entryPoint.Hex())
+		if err != nil {
+			return nil, fmt.Errorf("the bundler rejected the user operation: %v", err)
+		}
+		if submittedHash != userOpHash {
+			return nil, fmt.Errorf("the bundler returned %s for a user operation with hash %s", submittedHash.Hex(), userOpHash.Hex())
+		}
+		response.Data["submitted"] = true
+	}
+	return response, nil
+}
+
+func userOperationEntryPoint(version string, data *framework.FieldData) (*common.Address, error) {
+	input := data.Get("entry_point").(string)
+	if input == Empty {
+		if version == UserOperationV06 {
+			input = EntryPointV06
+		} else {
+			input = EntryPointV07
+		}
+	}
+	entryPoint, err := util.ParseAddress(input, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entry_point: %v", err)
+	}
+	return &entryPoint, nil
+}
+
+// userOperation reads the UserOperation from the request
+func userOperation(config *ConfigJSON, data *framework.FieldData) (*UserOperation, error) {
+	version := data.Get("version").(string)
+	if version != UserOperationV06 && version != UserOperationV07 {
+		return nil, fmt.Errorf("version must be %s or %s", UserOperationV06, UserOperationV07)
+	}
+	sender, err := config.addressField(data, "sender")
+	if err != nil {
+		return nil, err
+	}
+	op := &UserOperation{
+		Version: version,
+		Sender:  sender,
+	}
+
+	for field, value := range map[string]**big.Int{
+		"nonce":                            &op.Nonce,
+		"call_gas_limit":                   &op.CallGasLimit,
+		"verification_gas_limit":           &op.VerificationGasLimit,
+		"pre_verification_gas":             &op.PreVerificationGas,
+		"max_fee_per_gas":                  &op.MaxFeePerGas,
+		"max_priority_fee_per_gas":         &op.MaxPriorityFeePerGas,
+		"paymaster_verification_gas_limit": &op.PaymasterVerificationGasLimit,
+		"paymaster_post_op_gas_limit":      &op.PaymasterPostOpGasLimit,
+	} {
+		*value = util.ValidNumber(data.Get(field).(string))
+		if *value == nil {
+			return nil, fmt.Errorf("invalid %s", field)
+		}
+	}
+	for field, value := range map[string]*[]byte{
+		"init_code":          &op.InitCode,
+		"call_data":          &op.CallData,
+		"paymaster_and_data": &op.PaymasterAndData,
+		"factory_data":       &op.FactoryData,
+		"paymaster_data":     &op.PaymasterData,
+	} {
+		*value, err = decodeHex(data.Get(field).(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", field, err)
+		}
+	}
+
+	if version == UserOperationV06 {
+		return op, nil
+	}
+
+	for _, limit := range []*big.Int{op.CallGasLimit, op.VerificationGasLimit, op.MaxFeePerGas, op.MaxPriorityFeePerGas, op.PaymasterVerificationGasLimit, op.PaymasterPostOpGasLimit} {
+		if limit.BitLen() > 128 {
+			return nil, fmt.Errorf("v0.7 gas limits and fees must fit in 128 bits")
+		}
+	}
+	// v0.7 hashes the packed form but bundlers take the parts, so fill in whichever is missing
+	if factory := data.Get("factory").(string); factory != Empty {
+		if len(op.InitCode) > 0 {
+			return nil, fmt.Errorf("give either init_code or factory, not both")
+		}
+		address, err := config.parseAddress(factory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid factory: %v", err)
+		}
+		op.Factory = address
+		op.InitCode = append(address.Bytes(), op.FactoryData...)
+	} else if len(op.InitCode) > 0 {
+		if len(op.InitCode) < common.AddressLength {
+			return nil, fmt.Errorf("init_code must start with the factory address")
+		}
+		address := common.BytesToAddress(op.InitCode[:common.AddressLength])
+		op.Factory = &address
+		op.FactoryData = op.InitCode[common.AddressLength:]
+	}
+	if paymaster := data.Get("paymaster").(string); paymaster != Empty {
+		if len(op.PaymasterAndData) > 0 {
+			return nil, fmt.Errorf("give either paymaster_and_data or paymaster, not both")
+		}
+		address, err := config.parseAddress(paymaster)
+		if err != nil {
+			return nil, fmt.Errorf("invalid paymaster: %v", err)
+		}
+		op.Paymaster = address
+		op.PaymasterAndData = append(address.Bytes(), packUint128(op.PaymasterVerificationGasLimit, op.PaymasterPostOpGasLimit)...)
+		op.PaymasterAndData = append(op.PaymasterAndData, op.PaymasterData...)
+	} else if len(op.PaymasterAndData) > 0 {
+		if len(op.PaymasterAndData) < common.AddressLength+32 {
+			return nil, fmt.Errorf("paymaster_and_data must start with the paymaster address and its two gas limits")
+		}
+		address := common.BytesToAddress(op.PaymasterAndData[:common.AddressLength])
+		op.Paymaster = &address
+		op.PaymasterVerificationGasLimit = new(big.Int).SetBytes(op.PaymasterAndData[common.AddressLength : common.AddressLength+16])
+		op.PaymasterPostOpGasLimit = new(big.Int).SetBytes(op.PaymasterAndData[common.AddressLength+16 : common.AddressLength+32])
+		op.PaymasterData = op.PaymasterAndData[common.AddressLength+32:]
+	}
+	return op, nil
+}
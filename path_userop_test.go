@@ -0,0 +1,161 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/vault/sdk/framework"
+)
+
+// abiEncode encodes values as Solidity's abi.encode does, independently of the
+// hand-rolled encoding in UserOperation.Hash
+func abiEncode(t *testing.T, types []string, values ...interface{}) []byte {
+	var arguments abi.Arguments
+	for _, name := range types {
+		argumentType, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		arguments = append(arguments, abi.Argument{Type: argumentType})
+	}
+	encoded, err := arguments.Pack(values...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
+
+// uint128Pair packs two uint128 values into a bytes32 with shifts rather than byte copies
+func uint128Pair(high *big.Int, low *big.Int) [32]byte {
+	var packed [32]byte
+	new(big.Int).Or(new(big.Int).Lsh(high, 128), low).FillBytes(packed[:])
+	return packed
+}
+
+// userOpHash is getUserOpHash as the EntryPoint defines it:
+// keccak256(abi.encode(keccak256(pack(userOp)), entryPoint, chainId))
+func userOpHash(t *testing.T, packed []byte, entryPoint common.Address, chainID *big.Int) common.Hash {
+	inner := crypto.Keccak256Hash(packed)
+	return crypto.Keccak256Hash(abiEncode(t, []string{"bytes32", "address", "uint256"}, [32]byte(inner), entryPoint, chainID))
+}
+
+func TestPackUint128(t *testing.T) {
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	tests := []struct {
+		high *big.Int
+		low  *big.Int
+		want string
+	}{
+		{high: big.NewInt(0), low: big.NewInt(0), want: "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{high: big.NewInt(1), low: big.NewInt(2), want: "0x0000000000000000000000000000000100000000000000000000000000000002"},
+		{high: big.NewInt(100000), low: big.NewInt(200000), want: "0x000000000000000000000000000186a000000000000000000000000000030d40"},
+		{high: maxUint128, low: big.NewInt(0), want: "0xffffffffffffffffffffffffffffffff00000000000000000000000000000000"},
+		{high: big.NewInt(0), low: maxUint128, want: "0x00000000000000000000000000000000ffffffffffffffffffffffffffffffff"},
+	}
+	for _, test := range tests {
+		if got := common.BytesToHash(packUint128(test.high, test.low)).Hex(); got != test.want {
+			t.Errorf("packUint128(%s, %s) = %s, want %s", test.high, test.low, got, test.want)
+		}
+	}
+}
+
+func TestUserOperationHashV06(t *testing.T) {
+	op := &UserOperation{
+		Version:              UserOperationV06,
+		Sender:               common.HexToAddress("0x1234567890123456789012345678901234567890"),
+		Nonce:                big.NewInt(7),
+		InitCode:             common.FromHex("0x9406cc6185a346906296840746125a0e449764545fbfb9cf000000000000000000000000"),
+		CallData:             common.FromHex("0xb61d27f6"),
+		CallGasLimit:         big.NewInt(6942069),
+		VerificationGasLimit: big.NewInt(6942069),
+		PreVerificationGas:   big.NewInt(6942069),
+		MaxFeePerGas:         big.NewInt(69420),
+		MaxPriorityFeePerGas: big.NewInt(69),
+		PaymasterAndData:     common.FromHex("0xe93eca6595fe94091dc1af46aac2a8b5d7990770"),
+	}
+	entryPoint := common.HexToAddress(EntryPointV06)
+	chainID := big.NewInt(1)
+
+	packed := abiEncode(t,
+		[]string{"address", "uint256", "bytes32", "bytes32", "uint256", "uint256", "uint256", "uint256", "uint256", "bytes32"},
+		op.Sender, op.Nonce,
+		[32]byte(crypto.Keccak256Hash(op.InitCode)), [32]byte(crypto.Keccak256Hash(op.CallData)),
+		op.CallGasLimit, op.VerificationGasLimit, op.PreVerificationGas, op.MaxFeePerGas, op.MaxPriorityFeePerGas,
+		[32]byte(crypto.Keccak256Hash(op.PaymasterAndData)),
+	)
+	want := userOpHash(t, packed, entryPoint, chainID)
+	if got := op.Hash(entryPoint, chainID); got != want {
+		t.Fatalf("v0.6 userOpHash = %s, want %s", got.Hex(), want.Hex())
+	}
+	// The hash binds the EntryPoint and the chain
+	if op.Hash(common.HexToAddress(EntryPointV07), chainID) == want || op.Hash(entryPoint, big.NewInt(10)) == want {
+		t.Fatal("userOpHash doesn't depend on the EntryPoint and chain ID")
+	}
+}
+
+func TestUserOperationHashV07(t *testing.T) {
+	schema := userOperationPaths(&PluginBackend{})[0].Fields
+	data := &framework.FieldData{
+		Raw: map[string]interface{}{
+			"version":                          UserOperationV07,
+			"sender":                           "0x1234567890123456789012345678901234567890",
+			"nonce":                            "0x10000000000000000000000000000000000000000000001",
+			"factory":                          "0x9406cc6185a346906296840746125a0e44976454",
+			"factory_data":                     "0x5fbfb9cf",
+			"call_data":                        "0xb61d27f6",
+			"call_gas_limit":                   "100000",
+			"verification_gas_limit":           "200000",
+			"pre_verification_gas":             "50000",
+			"max_fee_per_gas":                  "30000000000",
+			"max_priority_fee_per_gas":         "1000000000",
+			"paymaster":                        "0xe93eca6595fe94091dc1af46aac2a8b5d7990770",
+			"paymaster_verification_gas_limit": "60000",
+			"paymaster_post_op_gas_limit":      "40000",
+			"paymaster_data":                   "0xdeadbeef",
+		},
+		Schema: schema,
+	}
+	op, err := userOperation(&ConfigJSON{}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entryPoint := common.HexToAddress(EntryPointV07)
+	chainID := big.NewInt(11155111)
+
+	initCode := append(common.HexToAddress("0x9406cc6185a346906296840746125a0e44976454").Bytes(), common.FromHex("0x5fbfb9cf")...)
+	paymasterGasLimits := uint128Pair(big.NewInt(60000), big.NewInt(40000))
+	paymasterAndData := append(common.HexToAddress("0xe93eca6595fe94091dc1af46aac2a8b5d7990770").Bytes(), paymasterGasLimits[:]...)
+	paymasterAndData = append(paymasterAndData, common.FromHex("0xdeadbeef")...)
+	nonce, _ := new(big.Int).SetString("10000000000000000000000000000000000000000000001", 16)
+
+	packed := abiEncode(t,
+		[]string{"address", "uint256", "bytes32", "bytes32", "bytes32", "uint256", "bytes32", "bytes32"},
+		common.HexToAddress("0x1234567890123456789012345678901234567890"), nonce,
+		[32]byte(crypto.Keccak256Hash(initCode)), [32]byte(crypto.Keccak256Hash(common.FromHex("0xb61d27f6"))),
+		uint128Pair(big.NewInt(200000), big.NewInt(100000)),
+		big.NewInt(50000),
+		uint128Pair(big.NewInt(1000000000), big.NewInt(30000000000)),
+		[32]byte(crypto.Keccak256Hash(paymasterAndData)),
+	)
+	want := userOpHash(t, packed, entryPoint, chainID)
+	if got := op.Hash(entryPoint, chainID); got != want {
+		t.Fatalf("v0.7 userOpHash = %s, want %s", got.Hex(), want.Hex())
+	}
+}
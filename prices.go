@@ -0,0 +1,429 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/immutability-io/vault-ethereum/contracts/chainlink"
+	"github.com/immutability-io/vault-ethereum/util"
+	"github.com/shopspring/decimal"
+	coingecko "github.com/superoo7/go-gecko/v3"
+)
+
+const (
+	// PriceSourceCoinGecko quotes prices from the CoinGecko API
+	PriceSourceCoinGecko string = "coingecko"
+	// PriceSourceChainlink reads prices from Chainlink aggregators through the configured RPC
+	PriceSourceChainlink string = "chainlink"
+	// PriceSourceStatic uses prices set in the configuration, for testing
+	PriceSourceStatic string = "static"
+	// DefaultCoinGeckoID is the CoinGecko coin that prices the native asset of a chain
+	// that isn't in the registry when none is configured
+	DefaultCoinGeckoID string = "ethereum"
+	// DefaultPriceTTL is how long a price is reused before it is fetched again
+	DefaultPriceTTL = time.Minute
+	// DefaultPriceMaxAge is the oldest a price may be before it is refused
+	DefaultPriceMaxAge = 2 * time.Hour
+	// PriceTimeout bounds a single request for a price
+	PriceTimeout = 10 * time.Second
+)
+
+// DefaultCurrencies are the fiat currencies convert accepts when none are configured
+var DefaultCurrencies = []string{USD, EUR, GBP, AED, ARS}
+
+// PriceOracleJSON configures where the price of the native asset comes from
+type PriceOracleJSON struct {
+	Source       string            `json:"source"`
+	CoinGeckoID  string            `json:"coingecko_id"`
+	Feeds        map[string]string `json:"feeds"`
+	StaticPrices map[string]string `json:"static_prices"`
+	TTL          int               `json:"ttl"`
+	MaxAge       int               `json:"max_age"`
+	Currencies   []string          `json:"currencies"`
+}
+
+// Asset is something that can be priced: the native asset or a registered token
+type Asset struct {
+	Name         string
+	CoinGeckoID  string
+	Feeds        map[string]string
+	StaticPrices map[string]string
+}
+
+// Price is the price of one unit of an asset in a currency
+type Price struct {
+	Value     decimal.Decimal
+	Asset     string
+	Currency  string
+	Source    string
+	Timestamp time.Time
+}
+
+// PriceSource quotes the price of an asset in a currency
+type PriceSource interface {
+	Name() string
+	Price(ctx context.Context, asset *Asset, currency string) (*Price, error)
+}
+
+// coinGeckoSource quotes prices from the CoinGecko API
+type coinGeckoSource struct{}
+
+func (source *coinGeckoSource) Name() string {
+	return PriceSourceCoinGecko
+}
+
+func (source *coinGeckoSource) Price(ctx context.Context, asset *Asset, currency string) (*Price, error) {
+	if asset.CoinGeckoID == Empty {
+		return nil, fmt.Errorf("no CoinGecko ID is configured for %s", asset.Name)
+	}
+	client := coingecko.NewClient(&http.Client{
+		Timeout: PriceTimeout,
+	})
+	singlePrice, err := client.SimpleSinglePrice(asset.CoinGeckoID, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the price of %s in %s from CoinGecko: %v", asset.CoinGeckoID, currency, err)
+	}
+	if singlePrice.MarketPrice <= 0 {
+		return nil, fmt.Errorf("CoinGecko has no price of %s in %s", asset.CoinGeckoID, currency)
+	}
+	return &Price{
+		Value:     decimal.NewFromFloat32(singlePrice.MarketPrice),
+		Asset:     asset.Name,
+		Currency:  currency,
+		Source:    PriceSourceCoinGecko,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// chainlinkSource reads prices from the asset's Chainlink aggregator for each currency
+type chainlinkSource struct {
+	b      *PluginBackend
+	config *ConfigJSON
+}
+
+func (source *chainlinkSource) Name() string {
+	return PriceSourceChainlink
+}
+
+func (source *chainlinkSource) Price(ctx context.Context, asset *Asset, currency string) (*Price, error) {
+	feed, ok := asset.Feeds[currency]
+	if !ok {
+		return nil, fmt.Errorf("no Chainlink price feed is configured for %s in %s", asset.Name, currency)
+	}
+	address, err := util.ParseAddress(feed, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Chainlink price feed for %s in %s: %v", asset.Name, currency, err)
+	}
+	client, err := source.b.client(ctx, source.config)
+	if err != nil {
+		return nil, err
+	}
+	aggregator, err := chainlink.NewAggregatorV3Caller(address, client)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, PriceTimeout)
+	defer cancel()
+	callOpts := &bind.CallOpts{Context: ctx}
+	decimals, err := aggregator.Decimals(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the decimals of the Chainlink price feed %s: %v", address.Hex(), err)
+	}
+	round, err := aggregator.LatestRoundData(callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Chainlink price feed %s: %v", address.Hex(), err)
+	}
+	if round.Answer.Sign() <= 0 || round.UpdatedAt.Sign() <= 0 {
+		return nil, fmt.Errorf("the Chainlink price feed %s has no valid answer", address.Hex())
+	}
+	return &Price{
+		Value:     decimal.NewFromBigInt(round.Answer, -int32(decimals)),
+		Asset:     asset.Name,
+		Currency:  currency,
+		Source:    PriceSourceChainlink,
+		Timestamp: time.Unix(round.UpdatedAt.Int64(), 0),
+	}, nil
+}
+
+// staticSource returns the prices set in the configuration
+type staticSource struct{}
+
+func (source *staticSource) Name() string {
+	return PriceSourceStatic
+}
+
+func (source *staticSource) Price(ctx context.Context, asset *Asset, currency string) (*Price, error) {
+	raw, ok := asset.StaticPrices[currency]
+	if !ok {
+		return nil, fmt.Errorf("no static price is configured for %s in %s", asset.Name, currency)
+	}
+	value, err := decimal.NewFromString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid static price for %s in %s: %v", asset.Name, currency, err)
+	}
+	return &Price{
+		Value:     value,
+		Asset:     asset.Name,
+		Currency:  currency,
+		Source:    PriceSourceStatic,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// priceSource returns the configured source of prices, CoinGecko by default
+func (b *PluginBackend) priceSource(config *ConfigJSON) (PriceSource, error) {
+	switch config.PriceOracle.Source {
+	case Empty, PriceSourceCoinGecko:
+		return &coinGeckoSource{}, nil
+	case PriceSourceChainlink:
+		return &chainlinkSource{b: b, config: config}, nil
+	case PriceSourceStatic:
+		return &staticSource{}, nil
+	}
+	return nil, fmt.Errorf("unknown price source %s", config.PriceOracle.Source)
+}
+
+// cachedPrice is a price and when it was fetched, which may be later than its timestamp
+type cachedPrice struct {
+	price     *Price
+	fetchedAt time.Time
+}
+
+// priceCache holds the last price fetched from each source
+type priceCache struct {
+	lock   sync.Mutex
+	prices map[string]*cachedPrice
+}
+
+// nativeAsset returns the native asset of the selected chain, priced as configured on
+// the mount or the selected network. Without a CoinGecko ID, the chain's own coin is
+// priced rather than ether.
+func (config *ConfigJSON) nativeAsset() *Asset {
+	name := ETH
+	id := config.PriceOracle.CoinGeckoID
+	if chain := ChainByID(config.ChainID); chain != nil {
+		name = strings.ToLower(chain.Symbol)
+		if id == Empty {
+			id = chain.CoinGeckoID
+		}
+	}
+	if id == Empty {
+		id = DefaultCoinGeckoID
+	}
+	return &Asset{
+		Name:         name,
+		CoinGeckoID:  id,
+		Feeds:        config.PriceOracle.Feeds,
+		StaticPrices: config.PriceOracle.StaticPrices,
+	}
+}
+
+// price returns the price of an asset in a currency. A price is reused for the
+// TTL; after that it is fetched again, and if that fails the cached price is still used
+// until it is older than the max age. Prices older than the max age are refused.
+func (b *PluginBackend) price(ctx context.Context, config *ConfigJSON, asset *Asset, currency string) (*Price, error) {
+	source, err := b.priceSource(config)
+	if err != nil {
+		return nil, err
+	}
+	ttl, maxAge := config.PriceOracle.limits()
+	key := strings.Join([]string{source.Name(), config.Network, config.ChainID, asset.Name, currency}, " ")
+
+	b.prices.lock.Lock()
+	cached, ok := b.prices.prices[key]
+	b.prices.lock.Unlock()
+	if ok && time.Since(cached.fetchedAt) < ttl {
+		return cached.price, nil
+	}
+
+	price, err := source.Price(ctx, asset, currency)
+	if err != nil {
+		if ok && time.Since(cached.price.Timestamp) < maxAge {
+			return cached.price, nil
+		}
+		return nil, err
+	}
+	if age := time.Since(price.Timestamp); age > maxAge {
+		return nil, fmt.Errorf("the %s price of %s in %s was last updated %s ago, which is older than %s", price.Source, asset.Name, currency, age.Round(time.Second), maxAge)
+	}
+
+	b.prices.lock.Lock()
+	defer b.prices.lock.Unlock()
+	if b.prices.prices == nil {
+		b.prices.prices = make(map[string]*cachedPrice)
+	}
+	b.prices.prices[key] = &cachedPrice{price: price, fetchedAt: time.Now()}
+	return price, nil
+}
+
+// resetPrices forgets every cached price, so that changed price settings take effect
+func (b *PluginBackend) resetPrices() {
+	b.prices.lock.Lock()
+	defer b.prices.lock.Unlock()
+	b.prices.prices = nil
+}
+
+// limits returns the TTL and max age, with the defaults for those that aren't set
+func (oracle *PriceOracleJSON) limits() (time.Duration, time.Duration) {
+	ttl, maxAge := DefaultPriceTTL, DefaultPriceMaxAge
+	if oracle.TTL > 0 {
+		ttl = time.Duration(oracle.TTL) * time.Second
+	}
+	if oracle.MaxAge > 0 {
+		maxAge = time.Duration(oracle.MaxAge) * time.Second
+	}
+	return ttl, maxAge
+}
+
+// currencies returns the fiat currencies convert accepts
+func (oracle *PriceOracleJSON) currencies() []string {
+	if len(oracle.Currencies) == 0 {
+		return DefaultCurrencies
+	}
+	return oracle.Currencies
+}
+
+// withPriceOracleFields adds the price source fields to a path's fields
+func withPriceOracleFields(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+	for name, schema := range map[string]*framework.FieldSchema{
+		"price_source": {
+			Type: framework.TypeString,
+			Description: fmt.Sprintf("Where prices come from: %s (the default), %s or %s.",
+				PriceSourceCoinGecko, PriceSourceChainlink, PriceSourceStatic),
+		},
+		"price_ttl": {
+			Type:        framework.TypeDurationSecond,
+			Description: fmt.Sprintf("How long a price is reused before it is fetched again - defaults to %s.", DefaultPriceTTL),
+		},
+		"fiat_currencies": {
+			Type:        framework.TypeCommaStringSlice,
+			Description: fmt.Sprintf("The fiat currencies convert accepts - defaults to %s.", strings.Join(DefaultCurrencies, ",")),
+		},
+		"price_max_age": {
+			Type:        framework.TypeDurationSecond,
+			Description: fmt.Sprintf("The oldest a price may be before it is refused - defaults to %s.", DefaultPriceMaxAge),
+		},
+	} {
+		fields[name] = schema
+	}
+	return withAssetPriceFields(fields)
+}
+
+// withAssetPriceFields adds the fields that price the native asset to a path's fields
+func withAssetPriceFields(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+	for name, schema := range map[string]*framework.FieldSchema{
+		"coingecko_id": {
+			Type:        framework.TypeString,
+			Description: fmt.Sprintf("The CoinGecko coin that prices the native asset - defaults to the chain's coin, or %s.", DefaultCoinGeckoID),
+		},
+		"price_feeds": {
+			Type:        framework.TypeKVPairs,
+			Description: "The Chainlink aggregator for each currency, such as usd=0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419.",
+		},
+		"static_prices": {
+			Type:        framework.TypeKVPairs,
+			Description: "The price in each currency when the source is static, such as usd=2000.",
+		},
+	} {
+		fields[name] = schema
+	}
+	return fields
+}
+
+// readPriceOracle updates oracle with the fields given on the request
+func readPriceOracle(data *framework.FieldData, oracle *PriceOracleJSON) error {
+	if source, ok := data.GetOk("price_source"); ok {
+		oracle.Source = strings.ToLower(source.(string))
+	}
+	switch oracle.Source {
+	case Empty, PriceSourceCoinGecko, PriceSourceChainlink, PriceSourceStatic:
+	default:
+		return fmt.Errorf("price_source must be %s, %s or %s", PriceSourceCoinGecko, PriceSourceChainlink, PriceSourceStatic)
+	}
+	if err := readAssetPrices(data, &oracle.CoinGeckoID, &oracle.Feeds, &oracle.StaticPrices); err != nil {
+		return err
+	}
+	if ttl, ok := data.GetOk("price_ttl"); ok {
+		oracle.TTL = ttl.(int)
+	}
+	if maxAge, ok := data.GetOk("price_max_age"); ok {
+		oracle.MaxAge = maxAge.(int)
+	}
+	if currencies, ok := data.GetOk("fiat_currencies"); ok {
+		oracle.Currencies = nil
+		for _, currency := range currencies.([]string) {
+			currency = strings.ToLower(strings.TrimSpace(currency))
+			if _, err := ValidUnit(currency); err == nil {
+				return fmt.Errorf("%s is an ether unit, not a currency", currency)
+			}
+			oracle.Currencies = append(oracle.Currencies, currency)
+		}
+	}
+	if oracle.TTL < 0 || oracle.MaxAge < 0 {
+		return fmt.Errorf("price_ttl and price_max_age can't be negative")
+	}
+	return nil
+}
+
+// readAssetPrices updates the settings that price the native asset with the fields
+// given on the request
+func readAssetPrices(data *framework.FieldData, coinGeckoID *string, feeds *map[string]string, staticPrices *map[string]string) error {
+	if id, ok := data.GetOk("coingecko_id"); ok {
+		*coinGeckoID = id.(string)
+	}
+	if feedsRaw, ok := data.GetOk("price_feeds"); ok {
+		*feeds = make(map[string]string)
+		for currency, feed := range feedsRaw.(map[string]string) {
+			address, err := util.ParseAddress(feed, false)
+			if err != nil {
+				return fmt.Errorf("invalid price feed for %s: %v", currency, err)
+			}
+			(*feeds)[strings.ToLower(currency)] = address.Hex()
+		}
+	}
+	if prices, ok := data.GetOk("static_prices"); ok {
+		*staticPrices = make(map[string]string)
+		for currency, price := range prices.(map[string]string) {
+			value, err := decimal.NewFromString(price)
+			if err != nil || !value.IsPositive() {
+				return fmt.Errorf("invalid static price for %s: %s", currency, price)
+			}
+			(*staticPrices)[strings.ToLower(currency)] = value.String()
+		}
+	}
+	return nil
+}
+
+// Response returns the price settings for a response
+func (oracle *PriceOracleJSON) Response() map[string]interface{} {
+	ttl, maxAge := oracle.limits()
+	return map[string]interface{}{
+		"source":        oracle.Source,
+		"coingecko_id":  oracle.CoinGeckoID,
+		"feeds":         oracle.Feeds,
+		"static_prices": oracle.StaticPrices,
+		"ttl":           int64(ttl.Seconds()),
+		"max_age":       int64(maxAge.Seconds()),
+		"currencies":    oracle.currencies(),
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import "testing"
+
+func TestNativeAsset(t *testing.T) {
+	tests := []struct {
+		name        string
+		chainID     string
+		coinGeckoID string
+		wantName    string
+		wantID      string
+	}{
+		{name: "mainnet", chainID: EthereumMainnet, wantName: "eth", wantID: "ethereum"},
+		{name: "L2", chainID: BaseMainnet, wantName: "eth", wantID: "ethereum"},
+		{name: "polygon", chainID: PolygonMainnet, wantName: "pol", wantID: "polygon-ecosystem-token"},
+		{name: "rootstock", chainID: RootstockMainnet, wantName: "rbtc", wantID: "rootstock"},
+		{name: "ethereum classic", chainID: EthereumClassicMainnet, wantName: "etc", wantID: "ethereum-classic"},
+		{name: "configured", chainID: PolygonMainnet, coinGeckoID: "matic-network", wantName: "pol", wantID: "matic-network"},
+		{name: "unknown chain", chainID: "424242", wantName: ETH, wantID: DefaultCoinGeckoID},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &ConfigJSON{ChainID: test.chainID}
+			config.PriceOracle.CoinGeckoID = test.coinGeckoID
+			asset := config.nativeAsset()
+			if asset.Name != test.wantName || asset.CoinGeckoID != test.wantID {
+				t.Fatalf("nativeAsset = %s priced as %s, want %s priced as %s", asset.Name, asset.CoinGeckoID, test.wantName, test.wantID)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/vault/sdk/framework"
+)
+
+// RPCAuthJSON holds the credentials used to connect to a node. It is stored seal
+// wrapped and never returned on read.
+type RPCAuthJSON struct {
+	Headers  map[string]string `json:"headers"`
+	Username string            `json:"username"`
+	Password string            `json:"password"`
+	JWT      string            `json:"jwt"`
+	TLSCert  string            `json:"tls_cert"`
+	TLSKey   string            `json:"tls_key"`
+	TLSCA    string            `json:"tls_ca"`
+}
+
+// Methods lists the kinds of authentication that are configured, without their values
+func (auth *RPCAuthJSON) Methods() []string {
+	methods := []string{}
+	if auth == nil {
+		return methods
+	}
+	if len(auth.Headers) > 0 {
+		methods = append(methods, "headers")
+	}
+	if auth.Username != Empty {
+		methods = append(methods, "basic")
+	}
+	if auth.JWT != Empty {
+		methods = append(methods, "jwt")
+	}
+	if auth.TLSCert != Empty {
+		methods = append(methods, "mtls")
+	}
+	if auth.TLSCA != Empty {
+		methods = append(methods, "ca")
+	}
+	return methods
+}
+
+// tlsConfig returns the client TLS configuration, or nil if the defaults will do
+func (auth *RPCAuthJSON) tlsConfig() (*tls.Config, error) {
+	if auth.TLSCert == Empty && auth.TLSKey == Empty && auth.TLSCA == Empty {
+		return nil, nil
+	}
+	config := &tls.Config{}
+	if auth.TLSCert != Empty || auth.TLSKey != Empty {
+		certificate, err := tls.X509KeyPair([]byte(auth.TLSCert), []byte(auth.TLSKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid rpc_tls_cert or rpc_tls_key: %v", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	if auth.TLSCA != Empty {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(auth.TLSCA)) {
+			return nil, fmt.Errorf("rpc_tls_ca contains no PEM certificates")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// authTransport adds the configured credentials to every request to the node
+type authTransport struct {
+	auth      *RPCAuthJSON
+	transport http.RoundTripper
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.auth.Headers {
+		req.Header.Set(name, value)
+	}
+	if t.auth.Username != Empty {
+		req.SetBasicAuth(t.auth.Username, t.auth.Password)
+	}
+	if t.auth.JWT != Empty {
+		req.Header.Set("Authorization", "Bearer "+t.auth.JWT)
+	}
+	return t.transport.RoundTrip(req)
+}
+
+// dialRPC connects to a node over http(s) or ws(s) with the configured credentials
+func dialRPC(ctx context.Context, rawurl string, auth *RPCAuthJSON) (*rpc.Client, error) {
+	if auth == nil || len(auth.Methods()) == 0 {
+		return rpc.DialContext(ctx, rawurl)
+	}
+	tlsConfig, err := auth.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	switch endpoint.Scheme {
+	case "http", "https":
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		return rpc.DialHTTPWithClient(rawurl, &http.Client{
+			Transport: &authTransport{auth: auth, transport: transport},
+		})
+	case "ws", "wss":
+		// The websocket handshake only carries basic auth taken from the URL
+		if len(auth.Headers) > 0 || auth.JWT != Empty {
+			return nil, fmt.Errorf("headers and JWT authentication are only supported for http endpoints")
+		}
+		if auth.Username != Empty {
+			endpoint.User = url.UserPassword(auth.Username, auth.Password)
+		}
+		return rpc.DialWebsocketWithDialer(ctx, endpoint.String(), Empty, websocket.Dialer{
+			TLSClientConfig: tlsConfig,
+		})
+	}
+	return nil, fmt.Errorf("authentication is only supported for http and websocket endpoints, not %s", endpoint.Scheme)
+}
+
+// withoutCredentials moves credentials embedded in an RPC URL into the auth settings,
+// so that they are stored seal wrapped and never returned as part of the URL
+func withoutCredentials(rawurl string, auth *RPCAuthJSON) (string, error) {
+	endpoint, err := url.Parse(rawurl)
+	if err != nil {
+		return Empty, fmt.Errorf("invalid RPC URL: %v", err)
+	}
+	if endpoint.User == nil {
+		return rawurl, nil
+	}
+	if auth.Username == Empty {
+		auth.Username = endpoint.User.Username()
+		auth.Password, _ = endpoint.User.Password()
+	}
+	endpoint.User = nil
+	return endpoint.String(), nil
+}
+
+// withRPCAuthFields adds the node credential fields to a path's fields
+func withRPCAuthFields(fields map[string]*framework.FieldSchema) map[string]*framework.FieldSchema {
+	for name, schema := range map[string]*framework.FieldSchema{
+		"rpc_headers": {
+			Type:        framework.TypeKVPairs,
+			Description: "Extra HTTP headers sent to the node, such as API keys.",
+		},
+		"rpc_username": {
+			Type:        framework.TypeString,
+			Description: "Username for basic authentication to the node.",
+		},
+		"rpc_password": {
+			Type:        framework.TypeString,
+			Description: "Password for basic authentication to the node.",
+		},
+		"rpc_jwt": {
+			Type:        framework.TypeString,
+			Description: "JWT sent to the node as a bearer token.",
+		},
+		"rpc_tls_cert": {
+			Type:        framework.TypeString,
+			Description: "PEM encoded client certificate for mutual TLS with the node.",
+		},
+		"rpc_tls_key": {
+			Type:        framework.TypeString,
+			Description: "PEM encoded private key of the client certificate.",
+		},
+		"rpc_tls_ca": {
+			Type:        framework.TypeString,
+			Description: "PEM encoded CA certificates used to verify the node.",
+		},
+	} {
+		fields[name] = schema
+	}
+	return fields
+}
+
+// readRPCAuth updates auth with the fields given on the request
+func readRPCAuth(data *framework.FieldData, auth *RPCAuthJSON) error {
+	if headers, ok := data.GetOk("rpc_headers"); ok {
+		auth.Headers = headers.(map[string]string)
+	}
+	for field, value := range map[string]*string{
+		"rpc_username": &auth.Username,
+		"rpc_password": &auth.Password,
+		"rpc_jwt":      &auth.JWT,
+		"rpc_tls_cert": &auth.TLSCert,
+		"rpc_tls_key":  &auth.TLSKey,
+		"rpc_tls_ca":   &auth.TLSCA,
+	} {
+		if raw, ok := data.GetOk(field); ok {
+			*value = raw.(string)
+		}
+	}
+	_, err := auth.tlsConfig()
+	return err
+}
@@ -30,6 +30,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -120,17 +121,50 @@ func PathExistenceCheck(ctx context.Context, req *logical.Request, data *framewo
 	return out != nil, nil
 }
 
-// ValidNumber returns a valid positive integer
+// ValidNumber returns a positive integer given in decimal or as 0x hex, or nil if the
+// input is malformed or doesn't fit in 256 bits
 func ValidNumber(input string) *big.Int {
 	if input == "" {
 		return big.NewInt(0)
 	}
-	matched, err := regexp.MatchString("([0-9])", input)
+	matched, err := regexp.MatchString("^([0-9]+|0[xX][0-9a-fA-F]+)$", input)
 	if !matched || err != nil {
 		return nil
 	}
-	amount := math.MustParseBig256(input)
-	return amount.Abs(amount)
+	amount, ok := math.ParseBig256(input)
+	if !ok {
+		return nil
+	}
+	return amount
+}
+
+// MaxPrecompile is the highest address reserved for precompiled contracts, including the
+// P-256 verifier some L2s deploy at 0x100
+const MaxPrecompile int64 = 0x100
+
+// ParseAddress parses a hex address strictly: 40 hex digits with an optional 0x prefix.
+// A mixed case address must carry a valid EIP-55 checksum, and if requireChecksum is set
+// every address must.
+func ParseAddress(input string, requireChecksum bool) (common.Address, error) {
+	if !common.IsHexAddress(input) {
+		return common.Address{}, fmt.Errorf("invalid address %q - an address is 40 hex digits", input)
+	}
+	address := common.HexToAddress(input)
+	digits := input
+	if len(digits) == 2*common.AddressLength+2 {
+		digits = digits[2:]
+	}
+	lower, upper := strings.ToLower(digits), strings.ToUpper(digits)
+	mixedCase := digits != lower && digits != upper
+	if (mixedCase || requireChecksum) && digits != address.Hex()[2:] {
+		return common.Address{}, fmt.Errorf("%s does not have a valid EIP-55 checksum", input)
+	}
+	return address, nil
+}
+
+// IsReservedAddress returns true for the zero address and the precompiled contracts
+func IsReservedAddress(address common.Address) bool {
+	return new(big.Int).SetBytes(address.Bytes()).Cmp(big.NewInt(MaxPrecompile)) <= 0
 }
 
 // Pow computes a^b for int64
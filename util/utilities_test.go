@@ -0,0 +1,119 @@
+// Copyright © 2018 Immutability, LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseAddress(t *testing.T) {
+	const checksummed = "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	tests := []struct {
+		name            string
+		input           string
+		requireChecksum bool
+		want            string
+		wantErr         bool
+	}{
+		{name: "checksummed", input: checksummed, want: checksummed},
+		{name: "checksummed without prefix", input: checksummed[2:], want: checksummed},
+		{name: "lower case", input: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", want: checksummed},
+		{name: "upper case", input: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", want: checksummed},
+		{name: "bad checksum", input: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", wantErr: true},
+		{name: "checksum required", input: checksummed, requireChecksum: true, want: checksummed},
+		{name: "lower case with checksum required", input: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", requireChecksum: true, wantErr: true},
+		{name: "too short", input: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", wantErr: true},
+		{name: "too long", input: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00", wantErr: true},
+		{name: "not hex", input: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaeg", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			address, err := ParseAddress(test.input, test.requireChecksum)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseAddress(%q) = %s, want an error", test.input, address.Hex())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAddress(%q) failed: %v", test.input, err)
+			}
+			if address.Hex() != test.want {
+				t.Fatalf("ParseAddress(%q) = %s, want %s", test.input, address.Hex(), test.want)
+			}
+		})
+	}
+}
+
+func TestIsReservedAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: ZeroAddress, want: true},
+		{address: "0x0000000000000000000000000000000000000001", want: true},
+		{address: "0x00000000000000000000000000000000000000ff", want: true},
+		{address: "0x0000000000000000000000000000000000000100", want: true},
+		{address: "0x0000000000000000000000000000000000000101", want: false},
+		{address: "0x0000000000000000000000000000000000010000", want: false},
+		{address: "0x1000000000000000000000000000000000000000", want: false},
+		{address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", want: false},
+	}
+	for _, test := range tests {
+		if got := IsReservedAddress(common.HexToAddress(test.address)); got != test.want {
+			t.Errorf("IsReservedAddress(%s) = %v, want %v", test.address, got, test.want)
+		}
+	}
+}
+
+func TestValidNumber(t *testing.T) {
+	const maxUint256 = "115792089237316195423570985008687907853269984665640564039457584007913129639935"
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: "0"},
+		{input: "0", want: "0"},
+		{input: "21000", want: "21000"},
+		{input: "0x5208", want: "21000"},
+		{input: "0X5208", want: "21000"},
+		{input: maxUint256, want: maxUint256},
+		{input: "0x" + strings.Repeat("f", 64), want: maxUint256},
+		{input: "115792089237316195423570985008687907853269984665640564039457584007913129639936"},
+		{input: "0x1" + strings.Repeat("0", 64)},
+		{input: "-1"},
+		{input: "1.5"},
+		{input: "1e18"},
+		{input: "0x"},
+		{input: "0xg"},
+		{input: " 1"},
+	}
+	for _, test := range tests {
+		got := ValidNumber(test.input)
+		if test.want == "" {
+			if got != nil {
+				t.Errorf("ValidNumber(%q) = %s, want nil", test.input, got)
+			}
+			continue
+		}
+		if got == nil || got.String() != test.want {
+			t.Errorf("ValidNumber(%q) = %v, want %s", test.input, got, test.want)
+		}
+	}
+}